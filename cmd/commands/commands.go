@@ -16,6 +16,8 @@ import (
 	"github.com/harrybrwn/edu/cmd/internal/opts"
 	"github.com/harrybrwn/edu/cmd/print"
 	"github.com/harrybrwn/edu/pkg/term"
+	"github.com/harrybrwn/edu/school/banner"
+	"github.com/harrybrwn/edu/school/banner9"
 	"github.com/harrybrwn/go-canvas"
 	"github.com/spf13/cobra"
 )
@@ -30,6 +32,7 @@ type Config struct {
 	BaseDir       string `yaml:"basedir" default:"$HOME/.edu/files"`
 	Token         string `yaml:"token" env:"CANVAS_TOKEN"`
 	Notifications bool   `yaml:"notifications" default:"true"`
+	School        string `yaml:"school" default:"ucmerced"`
 
 	Twilio struct {
 		SID    string `yaml:"sid" env:"TWILIO_SID"`
@@ -37,19 +40,28 @@ type Config struct {
 		Number string `yaml:"number"`
 	} `yaml:"twilio"`
 	Registration struct {
-		Term string `yaml:"term"`
-		Year int    `yaml:"year"`
+		Term     string `yaml:"term"`
+		Year     int    `yaml:"year"`
+		CacheTTL string `yaml:"cache_ttl" default:"15m"`
 	} `yaml:"registration"`
 	Watch struct {
-		Duration     string `yaml:"duration" default:"12h"`
-		CRNs         []int  `yaml:"crns"`
-		Term         string `yaml:"term"`
-		Year         int    `yaml:"year"`
-		Files        bool   `yaml:"files"`
-		Subject      string `yaml:"subject"`
-		SmsNotify    bool   `yaml:"sms_notify"`
-		SmsRecipient string `yaml:"sms_recipient"`
+		Duration     string   `yaml:"duration" default:"12h"`
+		CRNs         []int    `yaml:"crns"`
+		Courses      []string `yaml:"courses"`
+		Term         string   `yaml:"term"`
+		Year         int      `yaml:"year"`
+		Files        bool     `yaml:"files"`
+		Subject      string   `yaml:"subject"`
+		SmsNotify    bool     `yaml:"sms_notify"`
+		SmsRecipient string   `yaml:"sms_recipient"`
+		Threshold    int      `yaml:"threshold"`
+		QuietHours   string   `yaml:"quiet_hours"`
+		Jitter       float64  `yaml:"jitter" default:"0.1"`
+		MaxBackoff   string   `yaml:"max_backoff" default:"6h"`
+		Listen       string   `yaml:"listen"`
 	} `yaml:"watch"`
+	Banner             []banner.Config                `yaml:"banner"`
+	Banner9            []banner9.Config               `yaml:"banner9"`
 	Replacements       []files.Replacement            `yaml:"replacements"`
 	CourseReplacements map[string][]files.Replacement `yaml:"course-replacements"`
 }
@@ -1 +1,89 @@
 package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/config"
+	"github.com/harrybrwn/edu/cmd/internal"
+	"github.com/harrybrwn/edu/school/ucmerced/ucm"
+	"github.com/spf13/pflag"
+)
+
+func TestBannerSite(t *testing.T) {
+	sflags := &scheduleFlags{school: "ucmerced"}
+	site, p, err := sflags.bannerSite("plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if site != ucm.Merced || p != ucm.Provider {
+		t.Error("expected the uc merced site")
+	}
+
+	sflags.school = "berkeley"
+	_, err = sflags.bannerSchedule(context.Background(), "plan", "", false)
+	e, ok := err.(*internal.Error)
+	if !ok || !strings.Contains(e.Msg, "'plan' is not supported for UC Berkeley") {
+		t.Errorf("expected a not supported error, got %v", err)
+	}
+}
+
+func TestRequestedSemester(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		ok   bool
+	}{
+		{nil, false},
+		{[]string{"--term", "fall"}, false},
+		{[]string{"--year", "2020"}, false},
+		{[]string{"--term", "fall", "--year", "2020"}, true},
+	} {
+		// the defaults come from the config
+		sflags := &scheduleFlags{term: "spring", year: 2021}
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.StringVar(&sflags.term, "term", sflags.term, "")
+		flags.IntVar(&sflags.year, "year", sflags.year, "")
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatal(err)
+		}
+		semester, year, ok := requestedSemester(flags, sflags)
+		if ok != tc.ok {
+			t.Errorf("%v: got %v; want %v", tc.args, ok, tc.ok)
+		}
+		if ok && (semester != "fall" || year != 2020) {
+			t.Errorf("%v: wrong semester %s %d", tc.args, semester, year)
+		}
+	}
+}
+
+func TestWatchSemester(t *testing.T) {
+	if err := config.SetConfig(Conf); err != nil {
+		t.Fatal(err)
+	}
+	old := Conf.Watch
+	defer func() { Conf.Watch = old }()
+	Conf.Watch.Year, Conf.Watch.Term = 2022, "spring"
+
+	for _, tc := range []struct {
+		args     []string
+		year     int
+		semester string
+	}{
+		{nil, 2022, "spring"},
+		{[]string{"--term", "summer"}, 2022, "summer"},
+		{[]string{"--year", "2020"}, 2020, "spring"},
+	} {
+		sflags := &scheduleFlags{term: "fall", year: 2021}
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.StringVar(&sflags.term, "term", sflags.term, "")
+		flags.IntVar(&sflags.year, "year", sflags.year, "")
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatal(err)
+		}
+		year, semester := watchSemester(flags, sflags)
+		if year != tc.year || semester != tc.semester {
+			t.Errorf("%v: got %d %s; want %d %s", tc.args, year, semester, tc.year, tc.semester)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/harrybrwn/edu/cmd/internal"
+	"github.com/harrybrwn/edu/pkg/term"
+	"github.com/harrybrwn/edu/school/schedule"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd(sflags *scheduleFlags) *cobra.Command {
+	var (
+		subject string
+		save    bool
+	)
+	c := &cobra.Command{
+		Use:   "diff [snapshot]",
+		Short: "Compare a saved schedule snapshot with the live schedule",
+		Long: "Compare a saved schedule snapshot with the live schedule.\n\n" +
+			"If no snapshot file is given then the default snapshot for the\n" +
+			"school, term, year, and subject is used. When there is no snapshot\n" +
+			"the current schedule is saved so that it can be compared later.\n" +
+			"The snapshot is only replaced with --save, without it every run\n" +
+			"compares against the same snapshot.",
+		Example: "$ edu registration diff --save\n" +
+			"\t$ edu registration diff --subject=cse ./snapshot.json",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			p, err := sflags.provider()
+			if err != nil {
+				return err
+			}
+			var file string
+			if len(args) == 1 {
+				file = args[0]
+			} else {
+				file, err = snapshotFile(p.Name, sflags.year, sflags.term, subject)
+				if err != nil {
+					return err
+				}
+			}
+			// always compare against the newest schedule
+			fresh := *sflags
+			fresh.refresh = true
+			sched, err := fresh.getSchedule(cmd.Context(), subject, false)
+			if err != nil {
+				return err
+			}
+			live, ok := sched.(*schedule.Snapshot)
+			if !ok {
+				live = schedule.NewSnapshot(sched)
+			}
+			old, err := schedule.ReadSnapshot(file)
+			if os.IsNotExist(err) {
+				if err = live.WriteFile(file); err != nil {
+					return err
+				}
+				cmd.Printf("no snapshot found, saved the current schedule to %s\n", file)
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			changes := schedule.Diff(old, live)
+			if save {
+				if err = live.WriteFile(file); err != nil {
+					return err
+				}
+			}
+			if len(changes) == 0 {
+				cmd.Println("no changes")
+				return nil
+			}
+			tab := internal.NewTable(cmd.OutOrStdout())
+			internal.SetTableHeader(tab, []string{"", "crn", "code", "field", "old", "new"}, !sflags.NoColor)
+			tab.SetAutoWrapText(false)
+			for _, ch := range changes {
+				for _, row := range changeRows(ch, !sflags.NoColor) {
+					tab.Append(row)
+				}
+			}
+			tab.Render()
+			return nil
+		},
+	}
+	flags := c.Flags()
+	flags.StringVar(&subject, "subject", "", "only compare courses for one subject")
+	flags.BoolVar(&save, "save", save, "replace the snapshot with the live schedule")
+	return c
+}
+
+func changeRows(ch schedule.Change, color bool) [][]string {
+	var (
+		crn  = strconv.Itoa(ch.ID)
+		mark string
+	)
+	switch ch.Kind {
+	case schedule.Added:
+		mark = "+"
+		if color {
+			mark = term.Green(mark)
+		}
+		return [][]string{{mark, crn, sectionLabel(ch.New), "", "", cleanTitle(ch.New.Name())}}
+	case schedule.Removed:
+		mark = "-"
+		if color {
+			mark = term.Red(mark)
+		}
+		return [][]string{{mark, crn, sectionLabel(ch.Old), "", cleanTitle(ch.Old.Name()), ""}}
+	}
+	mark = "~"
+	if color {
+		mark = term.Yellow(mark)
+	}
+	rows := make([][]string, len(ch.Fields))
+	for i, f := range ch.Fields {
+		rows[i] = []string{mark, crn, sectionLabel(ch.New), f.Field, f.Old, f.New}
+	}
+	return rows
+}
+
+// snapshotFile returns the default snapshot file for a schedule.
+func snapshotFile(name string, year int, term, subject string) (string, error) {
+	dir, err := internal.ConfigSubDir("snapshots")
+	if err != nil {
+		return "", err
+	}
+	file := fmt.Sprintf("%s-%d-%s", strings.ToLower(name), year, strings.ToLower(term))
+	if subject != "" {
+		file += "-" + strings.ToLower(subject)
+	}
+	return filepath.Join(dir, file+".json"), nil
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/harrybrwn/edu/cmd/internal"
+	"github.com/harrybrwn/edu/pkg/term"
+	"github.com/harrybrwn/edu/school/ucberkeley/btime"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newEnrollmentCmd(sflags *scheduleFlags) *cobra.Command {
+	var rows = 10
+	c := &cobra.Command{
+		Use:   "enrollment <department> <number>",
+		Short: "Show how fast a UC Berkeley course filled up",
+		Long: "Show how fast a UC Berkeley course filled up during registration.\n" +
+			"The latest semester is used unless --term and --year are given.",
+		Example:           "$ edu registration enrollment compsci 61a --term spring --year 2021",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				ctx    = cmd.Context()
+				client = btime.NewClient()
+			)
+			course, err := client.FindCourse(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			semester, year, ok := requestedSemester(cmd.Flags(), sflags)
+			if !ok {
+				semesters, err := client.EnrollmentSemesters(ctx, course.ResultID)
+				if err != nil {
+					return err
+				}
+				if len(semesters) == 0 {
+					return &internal.Error{Msg: "no enrollment data found", Code: 1}
+				}
+				semester = semesters[0].Semester
+				if year, err = strconv.Atoi(semesters[0].Year); err != nil {
+					return err
+				}
+			}
+			trend, err := client.Enrollment(ctx, course.ResultID, semester, year)
+			if err != nil {
+				return err
+			}
+			if len(trend.Data) == 0 {
+				return &internal.Error{Msg: "no enrollment data found", Code: 1}
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%s %s %s %d\n", course.Abbreviation, course.CourseNumber, semester, year)
+			fmt.Fprintf(out, "enrolled: %s\n", term.Sparkline(trend.Enrolled()))
+			if full := trend.Filled(); full != nil {
+				fmt.Fprintf(out, "full on day %d (%s)\n\n", full.Day, full.Date)
+			} else {
+				fmt.Fprintf(out, "never filled\n\n")
+			}
+
+			tab := internal.NewTable(out)
+			header := []string{"day", "date", "enrolled", "capacity", "waitlist"}
+			internal.SetTableHeader(tab, header, !sflags.NoColor)
+			for _, i := range sampleIndexes(len(trend.Data), rows) {
+				p := trend.Data[i]
+				tab.Append([]string{
+					strconv.Itoa(p.Day),
+					p.Date,
+					strconv.Itoa(p.Enrolled),
+					strconv.Itoa(p.EnrolledMax),
+					strconv.Itoa(p.Waitlisted),
+				})
+			}
+			tab.Render()
+			return nil
+		},
+	}
+	c.Flags().IntVarP(&rows, "number", "n", rows, "number of days to show (0 shows every day)")
+	return c
+}
+
+// requestedSemester returns the term and year given with --term
+// and --year. The values that come from the config are not used
+// so ok is false unless both flags were given.
+func requestedSemester(flags *pflag.FlagSet, sflags *scheduleFlags) (semester string, year int, ok bool) {
+	if !flags.Changed("term") || !flags.Changed("year") {
+		return "", 0, false
+	}
+	return sflags.term, sflags.year, true
+}
+
+// sampleIndexes returns n evenly spaced indexes
+// that include the first and last index.
+func sampleIndexes(length, n int) []int {
+	if n <= 0 || n >= length {
+		n = length
+	}
+	idx := make([]int, 0, n)
+	if n == 1 {
+		return append(idx, length-1)
+	}
+	for i := 0; i < n; i++ {
+		idx = append(idx, i*(length-1)/(n-1))
+	}
+	return idx
+}
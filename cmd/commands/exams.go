@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/harrybrwn/config"
+	"github.com/harrybrwn/edu/cmd/internal"
+	"github.com/harrybrwn/edu/pkg/term"
+	"github.com/harrybrwn/edu/school/ucmerced/ucm"
+	"github.com/spf13/cobra"
+)
+
+func newExamsCmd(sflags *scheduleFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "exams [crn...]",
+		Short: "Show the final exam schedule for a list of CRNs",
+		Long: "Show the final exam schedule for a list of CRNs.\n\n" +
+			"Exams that overlap or are on the same day are flagged. If no\n" +
+			"CRNs are given then the 'crns' config variable is used.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			crns, err := stroiArr(args)
+			if err != nil {
+				return err
+			}
+			if len(crns) == 0 {
+				crns = config.GetIntSlice("crns")
+			}
+			if len(crns) == 0 {
+				return &internal.Error{Msg: "no crns given", Code: 1}
+			}
+			schedule, err := sflags.bannerSchedule(cmd.Context(), "exams", "", false)
+			if err != nil {
+				return err
+			}
+			courses := make([]*ucm.Course, 0, len(crns))
+			for _, crn := range crns {
+				c, ok := schedule[crn]
+				if !ok {
+					return &internal.Error{Msg: fmt.Sprintf("could not find %d in schedule", crn), Code: 1}
+				}
+				courses = append(courses, c)
+			}
+
+			// collect the conflicts for each exam
+			notes := make(map[*ucm.Exam][]string)
+			for _, c := range ucm.ExamConflicts(courses) {
+				msg := "same day as %d"
+				if c.Overlap {
+					msg = "overlaps %d"
+				}
+				notes[c.ExamA] = append(notes[c.ExamA], fmt.Sprintf(msg, c.B.CRN))
+				notes[c.ExamB] = append(notes[c.ExamB], fmt.Sprintf(msg, c.A.CRN))
+			}
+
+			tab := internal.NewTable(cmd.OutOrStdout())
+			header := []string{"crn", "code", "day", "date", "time", "building", "conflicts"}
+			internal.SetTableHeader(tab, header, !sflags.NoColor)
+			tab.SetAutoWrapText(false)
+			for _, c := range courses {
+				crn := strconv.Itoa(c.CRN)
+				if len(c.Exams) == 0 {
+					tab.Append([]string{crn, c.Fullcode, "", "no exam", "", "", ""})
+					continue
+				}
+				for _, e := range c.Exams {
+					timeStr := "TBD"
+					if !e.Time.Start.IsZero() {
+						timeStr = fmt.Sprintf("%s-%s", e.Time.Start.Format("3:04pm"), e.Time.End.Format("3:04pm"))
+					}
+					conflicts := strings.Join(notes[e], ", ")
+					if conflicts != "" && !sflags.NoColor {
+						conflicts = term.Red(conflicts)
+					}
+					tab.Append([]string{
+						crn,
+						c.Fullcode,
+						e.Day.String()[:3],
+						e.Date.Format("Jan 2"),
+						timeStr,
+						e.Building,
+						conflicts,
+					})
+				}
+			}
+			tab.Render()
+			return nil
+		},
+	}
+}
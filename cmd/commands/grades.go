@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harrybrwn/edu/cmd/internal"
+	"github.com/harrybrwn/edu/school/ucberkeley/btime"
+	"github.com/spf13/cobra"
+)
+
+func newGradesCmd(sflags *scheduleFlags) *cobra.Command {
+	var by = "instructor"
+	c := &cobra.Command{
+		Use:   "grades <department> <number>",
+		Short: "Show the grade distributions of a UC Berkeley course",
+		Long: "Show the grade distributions of a UC Berkeley course from\n" +
+			"berkeleytime grouped by instructor or by semester.",
+		Example:           "$ edu registration grades compsci 61a --by semester",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				ctx    = cmd.Context()
+				client = btime.NewClient()
+			)
+			course, err := client.FindCourse(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			sections, err := client.CourseGrades(ctx, course.ResultID)
+			if err != nil {
+				return err
+			}
+			var groups []btime.GradeGroup
+			switch by {
+			case "instructor":
+				groups = sections.ByInstructor()
+			case "semester":
+				groups = sections.BySemester()
+			default:
+				return fmt.Errorf("cannot group grades by %q (instructor|semester)", by)
+			}
+			if len(groups) == 0 {
+				return &internal.Error{Msg: "no grades found", Code: 1}
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%s %s: %s\n", course.Abbreviation, course.CourseNumber, course.Title)
+			for _, g := range groups {
+				d, err := client.GradeDistribution(ctx, g.IDs...)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "\n%s (%s, %.2f gpa, %d students)\n",
+					g.Name, d.SectionLetter, d.SectionGPA, d.Denominator)
+				for _, letter := range d.Letters() {
+					grade := d.Grades[letter]
+					fmt.Fprintf(out, "  %-2s %5d %5.1f%% %s\n",
+						letter, grade.Numerator, grade.Percent*100,
+						strings.Repeat("█", int(grade.Percent*40+0.5)))
+				}
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVar(&by, "by", by, "group the grades by instructor or semester")
+	return c
+}
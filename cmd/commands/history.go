@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/config"
+	"github.com/harrybrwn/edu/cmd/internal"
+	"github.com/harrybrwn/edu/cmd/internal/history"
+	"github.com/harrybrwn/edu/pkg/term"
+	"github.com/harrybrwn/edu/school"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newHistoryCmd(sflags *scheduleFlags) *cobra.Command {
+	var limit int
+	c := &cobra.Command{
+		Use:   "history <crn>",
+		Short: "Show the seat history of a CRN",
+		Long: "Show the seat history of a CRN.\n\n" +
+			"Seat history is recorded for the watched CRNs every time\n" +
+			"'edu registration watch' checks the schedule. The history is\n" +
+			"for the watch's term and year unless --term or --year is given.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			crn, err := strconv.Atoi(args[0])
+			if err != nil {
+				return err
+			}
+			p, err := sflags.provider()
+			if err != nil {
+				return err
+			}
+			year, semester := watchSemester(cmd.Flags(), sflags)
+			store, err := openHistory(p.Name, year, semester)
+			if err != nil {
+				return err
+			}
+			series, err := store.Series(crn)
+			if os.IsNotExist(err) || len(series) == 0 {
+				return &internal.Error{
+					Msg:  fmt.Sprintf("no history for %d (see 'edu registration watch')", crn),
+					Code: 1,
+				}
+			} else if err != nil {
+				return err
+			}
+
+			rows := series
+			if limit > 0 && len(rows) > limit {
+				rows = rows[len(rows)-limit:]
+			}
+			tab := internal.NewTable(cmd.OutOrStdout())
+			internal.SetTableHeader(tab, []string{"time", "capacity", "enrolled", "seats", "waitlist"}, !sflags.NoColor)
+			for _, smp := range rows {
+				tab.Append([]string{
+					smp.Time.Local().Format("Jan 2 15:04"),
+					strconv.Itoa(smp.Capacity),
+					strconv.Itoa(smp.Enrolled),
+					seatStr(smp.Seats, sflags.NoColor),
+					strconv.Itoa(smp.Waitlisted),
+				})
+			}
+			tab.Render()
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "\nseats: %s\n", term.Sparkline(series.Seats()))
+			periods := series.OpenPeriods()
+			if len(periods) == 0 {
+				fmt.Fprintln(out, "seats have not been open")
+				return nil
+			}
+			fmt.Fprintln(out)
+			for _, p := range periods {
+				status := fmt.Sprintf("open for %s", roundDuration(p.Duration()))
+				if p.Ongoing {
+					status = fmt.Sprintf("open for at least %s (still open)", roundDuration(p.Duration()))
+				}
+				fmt.Fprintf(out, "opened %s with up to %d seats, %s\n",
+					p.Start.Local().Format("Jan 2 15:04"), p.MaxSeats, status)
+			}
+			return nil
+		},
+	}
+	c.Flags().IntVarP(&limit, "limit", "n", 25, "maximum number of samples to show in the table (0 for all)")
+	return c
+}
+
+// watchSemester returns the year and term that the watch command
+// uses. The watch config is used unless --year or --term is given.
+func watchSemester(flags *pflag.FlagSet, sflags *scheduleFlags) (year int, semester string) {
+	year, semester = sflags.year, sflags.term
+	if y := config.GetInt("watch.year"); y != 0 && !flags.Changed("year") {
+		year = y
+	}
+	if t := config.GetString("watch.term"); t != "" && !flags.Changed("term") {
+		semester = t
+	}
+	return year, semester
+}
+
+// openHistory opens the seat history for a school's term.
+func openHistory(name string, year int, term string) (*history.Store, error) {
+	dir, err := internal.ConfigSubDir("history")
+	if err != nil {
+		return nil, err
+	}
+	return history.Open(filepath.Join(dir, fmt.Sprintf(
+		"%s-%d-%s", strings.ToLower(name), year, strings.ToLower(term))))
+}
+
+// recordHistory saves the seats of a list of courses.
+func recordHistory(name string, year int, term string, courses []school.Course) error {
+	store, err := openHistory(name, year, term)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, c := range courses {
+		e := c.Enrollment()
+		err = store.Add(c.ID(), history.Sample{
+			Time:       now,
+			Capacity:   e.Capacity,
+			Enrolled:   e.Enrolled,
+			Seats:      c.SeatsOpen(),
+			Waitlisted: e.Waitlisted,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func seatStr(seats int, nocolor bool) string {
+	s := strconv.Itoa(seats)
+	if nocolor {
+		return s
+	}
+	if seats <= 0 {
+		return term.Red(s)
+	}
+	return term.Green(s)
+}
+
+func roundDuration(d time.Duration) time.Duration {
+	if d > time.Hour {
+		return d.Round(time.Minute)
+	}
+	return d.Round(time.Second)
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/harrybrwn/config"
+	"github.com/harrybrwn/edu/cmd/internal"
+	"github.com/harrybrwn/edu/school/ucmerced/ucm"
+	"github.com/spf13/cobra"
+)
+
+func newICSCmd(sflags *scheduleFlags) *cobra.Command {
+	var (
+		output   string
+		timezone string
+	)
+	c := &cobra.Command{
+		Use:   "ics [crn...]",
+		Short: "Export CRNs as an iCalendar (.ics) timetable",
+		Long: "Export CRNs as an iCalendar (.ics) timetable.\n\n" +
+			"Each meeting is a weekly event that repeats until the end of\n" +
+			"the section and each final exam is a single event. If no CRNs\n" +
+			"are given then the 'crns' config variable is used.",
+		Example: "$ edu registration ics 30151 30153 -o spring.ics",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			crns, err := stroiArr(args)
+			if err != nil {
+				return err
+			}
+			if len(crns) == 0 {
+				crns = config.GetIntSlice("crns")
+			}
+			if len(crns) == 0 {
+				return &internal.Error{Msg: "no crns given", Code: 1}
+			}
+
+			_, p, err := sflags.bannerSite("ics")
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("tz") {
+				timezone = p.TimeZone
+			}
+			var loc *time.Location
+			if timezone != "" {
+				loc, err = time.LoadLocation(timezone)
+				if err != nil {
+					log.Printf("could not load time zone %q: %v\n", timezone, err)
+					fmt.Fprintf(os.Stderr, "Warning: could not load %s, using floating times\n", timezone)
+					loc = nil
+				}
+			}
+
+			schedule, err := sflags.bannerSchedule(cmd.Context(), "ics", "", false)
+			if err != nil {
+				return err
+			}
+			courses := make([]*ucm.Course, 0, len(crns))
+			for _, crn := range crns {
+				c, ok := schedule[crn]
+				if !ok {
+					return &internal.Error{Msg: fmt.Sprintf("could not find %d in schedule", crn), Code: 1}
+				}
+				courses = append(courses, c)
+			}
+
+			var w io.Writer = cmd.OutOrStdout()
+			if output != "" && output != "-" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			return ucm.WriteICS(w, courses, loc)
+		},
+	}
+	flags := c.Flags()
+	flags.StringVarP(&output, "output", "o", output, "write the calendar to a file instead of stdout")
+	flags.StringVar(&timezone, "tz", timezone, "time zone of the schedule (defaults to the school's, empty for floating times)")
+	return c
+}
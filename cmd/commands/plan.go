@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/harrybrwn/edu/cmd/internal"
+	"github.com/harrybrwn/edu/school"
+	"github.com/harrybrwn/edu/school/ucmerced/ucm"
+	"github.com/spf13/cobra"
+)
+
+func newPlanCmd(sflags *scheduleFlags) *cobra.Command {
+	var (
+		prefs = ucm.Preferences{Limit: 5}
+		rank  []string
+	)
+	c := &cobra.Command{
+		Use:   "plan <course...>",
+		Short: "Find schedules where none of the classes overlap",
+		Long: "Find every combination of lectures, labs, and discussions for a\n" +
+			"list of courses where none of the sections overlap. The options\n" +
+			"are ranked by the preferences given as flags.\n\n" +
+			"Ranking criteria for --rank: early, instructor, days, gaps",
+		Example: "$ edu registration plan CSE 100 MATH 24 WRI 10 --before 10\n" +
+			"\t$ edu reg plan cse-100 math-24 --rank days,gaps -n 3",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			courses, err := courseArgs(args)
+			if err != nil {
+				return err
+			}
+			for _, r := range rank {
+				c, err := ucm.ParseCriterion(r)
+				if err != nil {
+					return &internal.Error{Msg: err.Error(), Code: 1}
+				}
+				prefs.Rank = append(prefs.Rank, c)
+			}
+
+			subject := ""
+			subjects := make(map[string]struct{})
+			for _, id := range courses {
+				ident, err := school.ParseCourseIdent(id)
+				if err != nil {
+					return &internal.Error{Msg: err.Error(), Code: 1}
+				}
+				subjects[ident.Subject] = struct{}{}
+				subject = ident.Subject
+			}
+			if len(subjects) > 1 {
+				subject = "" // get the whole schedule
+			}
+			schedule, err := sflags.bannerSchedule(cmd.Context(), "plan", subject, sflags.open)
+			if err != nil {
+				return err
+			}
+			options, err := schedule.Plan(courses, prefs)
+			if err != nil {
+				return err
+			}
+			if len(options) == 0 {
+				return &internal.Error{Msg: "no schedules without conflicts", Code: 1}
+			}
+
+			out := cmd.OutOrStdout()
+			for i, opt := range options {
+				crns := make([]string, len(opt.Sections))
+				for j, crn := range opt.CRNs() {
+					crns[j] = strconv.Itoa(crn)
+				}
+				fmt.Fprintf(out, "Option %d: %d days, %v between classes", i+1, opt.Days, opt.Gaps)
+				if opt.Early > 0 {
+					fmt.Fprintf(out, ", %d before %d:00", opt.Early, prefs.Earliest)
+				}
+				fmt.Fprintf(out, "\nCRNs: %s\n", strings.Join(crns, ", "))
+				tab := internal.NewTable(out)
+				internal.SetTableHeader(tab, courseTableHeader, !sflags.NoColor)
+				tab.SetAutoWrapText(false)
+				for _, sec := range opt.Sections {
+					tab.Append(courseRow(sec, true, *sflags))
+				}
+				tab.Render()
+				fmt.Fprintln(out)
+			}
+			return nil
+		},
+	}
+	flags := c.Flags()
+	flags.IntVar(&prefs.Earliest, "before", prefs.Earliest, "avoid classes that start before this hour (24 hour clock)")
+	flags.StringSliceVar(&prefs.Instructors, "instructor", prefs.Instructors, "preferred instructors")
+	flags.StringSliceVar(&rank, "rank", rank, "order of the ranking criteria")
+	flags.IntVarP(&prefs.Limit, "number", "n", prefs.Limit, "number of options to show")
+	return c
+}
+
+// courseArgs groups arguments like "CSE 100 MATH 24" into course
+// identifiers. Arguments that already have a number are left alone.
+func courseArgs(args []string) ([]string, error) {
+	ids := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if strings.IndexAny(args[i], "0123456789") > 0 {
+			ids = append(ids, args[i])
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, errors.New("expected a course number after " + args[i])
+		}
+		ids = append(ids, args[i]+" "+args[i+1])
+		i++
+	}
+	return ids, nil
+}
@@ -1,13 +1,19 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
-	"regexp"
+	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gen2brain/beeep"
@@ -16,10 +22,12 @@ import (
 	"github.com/harrybrwn/edu/cmd/internal/files"
 	"github.com/harrybrwn/edu/cmd/internal/opts"
 	"github.com/harrybrwn/edu/cmd/internal/watch"
-	"github.com/harrybrwn/edu/pkg/term"
 	"github.com/harrybrwn/edu/pkg/twilio"
 	"github.com/harrybrwn/edu/school"
+	"github.com/harrybrwn/edu/school/banner"
+	"github.com/harrybrwn/edu/school/banner9"
 	"github.com/harrybrwn/edu/school/schedule"
+	"github.com/harrybrwn/edu/school/ucberkeley/btime"
 	"github.com/harrybrwn/edu/school/ucmerced/ucm"
 	"github.com/harrybrwn/errs"
 	"github.com/mitchellh/mapstructure"
@@ -33,13 +41,132 @@ type scheduleFlags struct {
 	term    string
 	year    int
 	open    bool
+	school  string
 	columns []string
+	refresh bool
+	maxAge  time.Duration
 }
 
 func (sf *scheduleFlags) install(fset *pflag.FlagSet) {
 	fset.StringVar(&sf.term, "term", sf.term, "specify the term (spring|summer|fall)")
 	fset.IntVar(&sf.year, "year", sf.year, "specify the year for registration")
 	fset.BoolVar(&sf.open, "open", sf.open, "only get classes that have seats open")
+	fset.StringVar(&sf.school, "school", sf.school, "specify the school (see 'edu registration schools')")
+	fset.BoolVar(&sf.refresh, "refresh", sf.refresh, "download the schedule even if it is cached")
+	fset.DurationVar(&sf.maxAge, "max-age", sf.maxAge, "use a cached schedule up to this old if the download fails")
+}
+
+// provider returns the school provider given by the school flag.
+func (sf *scheduleFlags) provider() (*school.Provider, error) {
+	p, err := school.Lookup(sf.school)
+	if err != nil {
+		return nil, &internal.Error{
+			Msg:  fmt.Sprintf("%v (see 'edu registration schools')", err),
+			Code: 1,
+		}
+	}
+	return sf.cached(p), nil
+}
+
+// noDepartmentError is returned for schools that can only get
+// the schedule for one subject at a time.
+func noDepartmentError(p *school.Provider) error {
+	return &internal.Error{
+		Msg:  fmt.Sprintf("%s schedules can only be fetched by department, give one with --subject", p.Title),
+		Code: 1,
+	}
+}
+
+// bannerSite returns the Banner 8 site of the school given by the
+// school flag. Commands that need the course details that only
+// Banner 8 schedules have use this instead of provider.
+func (sf *scheduleFlags) bannerSite(command string) (*ucm.Site, *school.Provider, error) {
+	p, err := school.Lookup(sf.school)
+	if err != nil {
+		return nil, nil, &internal.Error{
+			Msg:  fmt.Sprintf("%v (see 'edu registration schools')", err),
+			Code: 1,
+		}
+	}
+	site := ucm.ProviderSite(p)
+	if site == nil {
+		return nil, nil, &internal.Error{
+			Msg:  fmt.Sprintf("'%s' is not supported for %s", command, p.Title),
+			Code: 1,
+		}
+	}
+	return site, p, nil
+}
+
+// bannerSchedule will check that the schedule is offered and then
+// download it from the school's Banner 8 site. The schedule cache
+// is not used because it only keeps what every school has.
+func (sf *scheduleFlags) bannerSchedule(ctx context.Context, command, subject string, open bool) (ucm.Schedule, error) {
+	site, p, err := sf.bannerSite(command)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkOffered(ctx, p, &schedule.Config{
+		Year:       sf.year,
+		Term:       sf.term,
+		CourseName: subject,
+	}); err != nil {
+		return nil, err
+	}
+	return ucm.NewClient(site).Schedule(ctx, sf.year, sf.term, subject, open)
+}
+
+// cached wraps the provider so that its schedules are saved to the
+// schedule cache. The provider is returned as is if the cache
+// cannot be opened.
+func (sf *scheduleFlags) cached(p *school.Provider) *school.Provider {
+	ttl, err := time.ParseDuration(config.GetString("registration.cache_ttl"))
+	if err != nil {
+		log.Printf("bad registration.cache_ttl: %v\n", err)
+		return p
+	}
+	dir, err := internal.ConfigSubDir("cache")
+	if err != nil {
+		return p
+	}
+	cache, err := schedule.OpenCache(dir, ttl)
+	if err != nil {
+		log.Printf("could not open schedule cache: %v\n", err)
+		return p
+	}
+	cache.Refresh = sf.refresh
+	cache.MaxAge = sf.maxAge
+	return cache.Wrap(p)
+}
+
+// getSchedule will check that the schedule is offered
+// and then fetch it from the school provider.
+func (sf *scheduleFlags) getSchedule(ctx context.Context, subject string, open bool) (school.Schedule, error) {
+	p, err := sf.provider()
+	if err != nil {
+		return nil, err
+	}
+	conf := &schedule.Config{
+		Year:         sf.year,
+		Term:         sf.term,
+		CourseName:   subject,
+		FilterClosed: open,
+	}
+	if err = checkOffered(ctx, p, conf); err != nil {
+		return nil, err
+	}
+	sched, err := p.New(ctx, conf)
+	if errors.Is(err, btime.ErrNoDepartment) {
+		return nil, noDepartmentError(p)
+	} else if err != nil {
+		return nil, err
+	}
+	if snap, ok := sched.(*schedule.Snapshot); ok && snap.Stale() {
+		log.Printf("using a cached schedule from %s ago\n", snap.Age())
+		fmt.Fprintf(os.Stderr, "Warning: could not download the schedule, using one from %s ago\n",
+			snap.Age().Round(time.Minute))
+	}
+	return sched, nil
 }
 
 var courseTableHeader = []string{
@@ -53,11 +180,14 @@ var courseTableHeader = []string{
 }
 
 func newRegistrationCmd(globals *opts.Global) *cobra.Command {
+	registerBannerSchools()
 	var sflags = scheduleFlags{
 		term:   config.GetString("registration.term"),
 		year:   config.GetInt("registration.year"),
+		school: config.GetString("school"),
 		Global: globals,
 	}
+	var where string
 
 	c := &cobra.Command{
 		Use:   "registration",
@@ -67,7 +197,8 @@ registration information.`,
 		Aliases: []string{"reg", "register"},
 		Example: "" +
 			"$ edu registration cse 100 --term=fall\n" +
-			"\t$ edu reg --open --year=2021 --term=summer WRI 10",
+			"\t$ edu reg --open --year=2021 --term=summer WRI 10\n" +
+			"\t$ edu reg cse --where 'activity=LECT and days~MW and start>=10:00 and seats>0'",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			if sflags.year == 0 {
 				return errs.New("no year given")
@@ -75,26 +206,21 @@ registration information.`,
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			var (
-				subj string
-				num  int
-			)
+			var subj, num string
 			if len(args) >= 1 {
 				subj = args[0]
 			}
 			if len(args) >= 2 {
-				num, err = strconv.Atoi(args[1])
-				if err != nil {
-					return err
+				num = args[1]
+			}
+			var filter school.Filter
+			if where != "" {
+				if filter, err = school.ParseFilter(where); err != nil {
+					return &internal.Error{Msg: fmt.Sprintf("bad --where: %v", err), Code: 1}
 				}
 			}
 
-			schedule, err := schedule.New(school.UCMerced, &schedule.Config{
-				Year:         sflags.year,
-				Term:         sflags.term,
-				CourseName:   subj,
-				FilterClosed: sflags.open,
-			})
+			schedule, err := sflags.getSchedule(cmd.Context(), subj, sflags.open)
 			if err != nil {
 				return err
 			}
@@ -105,18 +231,23 @@ registration information.`,
 			if schedule.Len() == 0 {
 				return &internal.Error{Msg: "no courses found", Code: 1}
 			}
-			var courses []*ucm.Course
-			if sc, ok := schedule.(*ucm.Schedule); ok {
-				courses = sc.Ordered()
-			} else {
-				panic("don't yet support other schools")
-			}
 
-			for _, c := range courses {
-				if num != 0 && c.Number != num {
+			groups := scheduleGroups(schedule)
+			if filter != nil {
+				groups = filter.Groups(groups)
+			}
+			for _, g := range groups {
+				if num != "" && !sameNumber(g.Course, num) {
 					continue
 				}
-				tab.Append(courseRow(c, true, sflags))
+				tab.Append(courseRow(g.Course, true, sflags))
+				// linked labs and discussions are indented
+				// under their lecture
+				for _, sec := range g.Sections {
+					row := courseRow(sec, true, sflags)
+					row[1] = "  " + row[1]
+					tab.Append(row)
+				}
 			}
 			if tab.NumLines() == 0 {
 				return &internal.Error{Msg: "no matches", Code: 1}
@@ -126,10 +257,171 @@ registration information.`,
 		},
 	}
 	sflags.install(c.PersistentFlags())
-	c.AddCommand(newCheckCRNCmd(&sflags), newWatchCmd(&sflags))
+	c.Flags().StringVarP(&where, "where", "w", "", fmt.Sprintf(
+		"only show courses matching a filter expression (fields: %s)",
+		strings.Join(school.FilterFields(), ", ")))
+	c.AddCommand(
+		newCheckCRNCmd(&sflags),
+		newWatchCmd(&sflags),
+		newSchoolsCmd(&sflags),
+		newSubjectsCmd(&sflags),
+		newTermsCmd(&sflags),
+		newDiffCmd(&sflags),
+		newHistoryCmd(&sflags),
+		newPlanCmd(&sflags),
+		newICSCmd(&sflags),
+		newExamsCmd(&sflags),
+		newSearchCmd(&sflags),
+		newGradesCmd(&sflags),
+		newEnrollmentCmd(&sflags),
+	)
 	return c
 }
 
+func newSchoolsCmd(sflags *scheduleFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:               "schools",
+		Short:             "List the schools that are available",
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tab := internal.NewTable(cmd.OutOrStdout())
+			header := []string{"name", "aliases", "school", "terms", "features", "url"}
+			internal.SetTableHeader(tab, header, !sflags.NoColor)
+			for _, p := range school.Providers() {
+				name := p.Name
+				if strings.EqualFold(name, sflags.school) {
+					name += "*"
+				}
+				tab.Append([]string{
+					name,
+					strings.Join(p.Aliases, ","),
+					p.Title,
+					strings.Join(p.TermNames(), ","),
+					p.Capabilities.String(),
+					p.BaseURL,
+				})
+			}
+			tab.Render()
+			return nil
+		},
+	}
+}
+
+func newSubjectsCmd(sflags *scheduleFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:               "subjects",
+		Short:             "List the subject codes offered",
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			site, _, err := sflags.bannerSite("subjects")
+			if err != nil {
+				return err
+			}
+			offered, err := ucm.NewClient(site).Offerings(cmd.Context())
+			if err != nil {
+				return err
+			}
+			tab := internal.NewTable(cmd.OutOrStdout())
+			internal.SetTableHeader(tab, []string{"code", "subject"}, !sflags.NoColor)
+			for _, code := range offered.SubjectCodes() {
+				tab.Append([]string{code, offered.Subjects[code]})
+			}
+			tab.Render()
+			return nil
+		},
+	}
+}
+
+func newTermsCmd(sflags *scheduleFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:               "terms",
+		Short:             "List the terms that have a schedule",
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			site, _, err := sflags.bannerSite("terms")
+			if err != nil {
+				return err
+			}
+			offered, err := ucm.NewClient(site).Offerings(cmd.Context())
+			if err != nil {
+				return err
+			}
+			tab := internal.NewTable(cmd.OutOrStdout())
+			internal.SetTableHeader(tab, []string{"code", "term", "year", "name"}, !sflags.NoColor)
+			for _, t := range offered.Terms {
+				tab.Append([]string{t.Code, t.Season, strconv.Itoa(t.Year), t.Name})
+			}
+			tab.Render()
+			return nil
+		},
+	}
+}
+
+// registerBannerSchools will add the banner schools
+// from the config file as school providers.
+func registerBannerSchools() {
+	warn := func(err error) {
+		log.Printf("could not add banner school: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: could not add banner school: %v\n", err)
+	}
+	for _, conf := range Conf.Banner {
+		if _, err := school.Lookup(conf.Name); err == nil {
+			continue // already registered
+		}
+		if _, err := banner.Register(conf); err != nil {
+			warn(err)
+		}
+	}
+	for _, conf := range Conf.Banner9 {
+		if _, err := school.Lookup(conf.Name); err == nil {
+			continue
+		}
+		if _, err := banner9.Register(conf); err != nil {
+			warn(err)
+		}
+	}
+}
+
+// scheduleGroups returns the schedule's courses grouped with their
+// linked sections. Schedules that do not link sections are given
+// one group per course.
+func scheduleGroups(sched school.Schedule) []school.Group {
+	if g, ok := sched.(school.Grouper); ok {
+		return g.SectionGroups()
+	}
+	courses := sched.Courses()
+	groups := make([]school.Group, len(courses))
+	for i, c := range courses {
+		groups[i].Course = c
+	}
+	return groups
+}
+
+// sameNumber returns true if the course number matches
+// num ignoring case and leading zeros.
+func sameNumber(c school.Course, num string) bool {
+	_, n := c.Code()
+	return strings.EqualFold(
+		strings.TrimLeft(n, "0"),
+		strings.TrimLeft(num, "0"),
+	)
+}
+
+// checkOffered will make sure that the term, year, and subject
+// are offered before downloading the whole schedule.
+func checkOffered(ctx context.Context, p *school.Provider, conf *schedule.Config) error {
+	if p.Check == nil {
+		return nil
+	}
+	if err := p.Check(ctx, conf); err != nil {
+		return &internal.Error{
+			Msg:  fmt.Sprintf("%v (see 'edu registration terms' or 'edu registration subjects')", err),
+			Code: 1,
+		}
+	}
+	return nil
+}
+
 func newCheckCRNCmd(sflags *scheduleFlags) *cobra.Command {
 	var subject string
 	cmd := &cobra.Command{
@@ -137,7 +429,7 @@ func newCheckCRNCmd(sflags *scheduleFlags) *cobra.Command {
 		Hidden:     true,
 		Deprecated: "",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			schedule, err := ucm.BySubject(sflags.year, sflags.term, subject, true)
+			schedule, err := sflags.getSchedule(cmd.Context(), subject, true)
 			if err != nil {
 				return err
 			}
@@ -149,7 +441,7 @@ func newCheckCRNCmd(sflags *scheduleFlags) *cobra.Command {
 			crns = append(crns, crnargs...)
 
 			tab := internal.NewTable(cmd.OutOrStdout())
-			header := []string{"crn", "code", "open", "type", "time", "days"}
+			header := []string{"crn", "code", "open", "waitlist", "type", "time", "days"}
 			internal.SetTableHeader(tab, header, !sflags.NoColor)
 			tab.SetAutoWrapText(false)
 			for _, crn := range crns {
@@ -157,12 +449,11 @@ func newCheckCRNCmd(sflags *scheduleFlags) *cobra.Command {
 				if course == nil {
 					continue
 				}
-				crs, ok := course.(*ucm.Course)
-				if !ok {
-					fmt.Fprintf(os.Stderr, "Warning: only uc merced is supported\n")
-					continue
-				}
-				tab.Append(courseRow(crs, false, *sflags))
+				row := courseRow(course, false, *sflags)
+				row = append(row[:3], append([]string{
+					strconv.Itoa(course.Enrollment().Waitlisted),
+				}, row[3:]...)...)
+				tab.Append(row)
 			}
 			if tab.NumLines() == 0 {
 				return &internal.Error{Msg: fmt.Sprintf("could not find %v in schedule", crns), Code: 1}
@@ -182,12 +473,29 @@ type crnWatcher struct {
 	flags   scheduleFlags
 	verbose bool
 	twilio  *twilio.Client
+
+	// ctx is canceled when the watch is stopped and timeout
+	// is the longest that one check is allowed to take.
+	ctx     context.Context
+	timeout time.Duration
+	// threshold is a seat count that is notified
+	// when the seats go above or below it.
+	threshold int
+	// alerts holds notifications during quiet hours.
+	alerts *watch.Alerts
+	// state is kept between checks so that it can
+	// hold the alerts queued during quiet hours.
+	state     *watch.State
+	stateFile string
+	// monitor is served with --listen, may be nil.
+	monitor *watch.Monitor
 }
 
 func (cw *crnWatcher) Watch() error {
 	var (
 		subject = cw.subject
 		crns    = cw.crns
+		names   = cw.names
 	)
 	if config.GetInt("watch.year") != 0 {
 		cw.flags.year = config.GetInt("watch.year")
@@ -202,10 +510,20 @@ func (cw *crnWatcher) Watch() error {
 	if len(configCrns) > 0 {
 		crns = append(crns, configCrns...)
 	}
-	if len(crns) < 1 {
-		return errors.New("no crns to check (see 'edu config' watch settings)")
+	names = append(names, configStrings("watch.courses")...)
+	if len(crns) < 1 && len(names) < 1 {
+		return errors.New("no crns or courses to check (see 'edu config' watch settings)")
+	}
+	ctx := cw.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	err := cw.checkCRNs(crns, subject)
+	if cw.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cw.timeout)
+		defer cancel()
+	}
+	err := cw.checkCRNs(ctx, crns, names, subject)
 	if err != nil {
 		if cw.verbose {
 			fmt.Println(err)
@@ -215,45 +533,219 @@ func (cw *crnWatcher) Watch() error {
 	return nil
 }
 
-func (cw *crnWatcher) checkCRNs(crns []int, subject string) error {
-	schedule, err := ucm.BySubject(cw.flags.year, cw.flags.term, cw.subject, true)
+// checkCRNs checks the seats of a list of crns and of the sections
+// of a list of courses. The courses are expanded to their sections
+// each time so that new sections are also watched.
+func (cw *crnWatcher) checkCRNs(ctx context.Context, crns []int, names []string, subject string) error {
+	p, err := cw.flags.provider()
+	if err != nil {
+		return err
+	}
+	idents := make([]school.CourseIdent, len(names))
+	for i, name := range names {
+		if idents[i], err = school.ParseCourseIdent(name); err != nil {
+			return &internal.Error{Msg: err.Error(), Code: 1}
+		}
+	}
+	// each subject's schedule is only downloaded once
+	schedules := make(map[string]school.Schedule)
+	getSchedule := func(subj string) (school.Schedule, error) {
+		subj = strings.ToUpper(subj)
+		if sched, ok := schedules[subj]; ok {
+			return sched, nil
+		}
+		if sched, ok := schedules[""]; ok {
+			return sched, nil // has every subject
+		}
+		sched, err := p.New(ctx, &schedule.Config{
+			Year:       cw.flags.year,
+			Term:       cw.flags.term,
+			CourseName: subj,
+		})
+		if errors.Is(err, btime.ErrNoDepartment) {
+			return nil, noDepartmentError(p)
+		} else if err != nil {
+			return nil, err
+		}
+		schedules[subj] = sched
+		return sched, nil
+	}
+
+	var (
+		watched = make([]school.Course, 0, len(crns))
+		seen    = make(map[int]bool)
+	)
+	add := func(c school.Course) {
+		if !seen[c.ID()] {
+			seen[c.ID()] = true
+			watched = append(watched, c)
+		}
+	}
+	if len(crns) > 0 {
+		sched, err := getSchedule(subject)
+		if err != nil {
+			return err
+		}
+		for _, crn := range crns {
+			if course := sched.Get(crn); course != nil {
+				add(course)
+			}
+		}
+	}
+	for _, id := range idents {
+		sched, err := getSchedule(id.Subject)
+		if err != nil {
+			return err
+		}
+		n := 0
+		for _, course := range sched.Courses() {
+			if id.Match(course) {
+				add(course)
+				n++
+			}
+		}
+		if n == 0 {
+			log.Printf("no sections of %s in the schedule\n", id)
+		}
+	}
+	if len(watched) == 0 {
+		return &internal.Error{Msg: fmt.Sprintf("could not find %v in schedule", append(names, intsToStrings(crns)...)), Code: 1}
+	}
+	if err = recordHistory(p.Name, cw.flags.year, cw.flags.term, watched); err != nil {
+		log.Printf("could not save seat history: %v\n", err)
+	}
+
+	state, err := cw.openState(p.Name)
 	if err != nil {
 		return err
 	}
-	openCrns := make([]int, 0)
-	for _, crn := range crns {
-		_, ok := schedule[crn]
+	state.Threshold = cw.threshold
+	if cw.alerts != nil {
+		if err = cw.alerts.Persist(state); err != nil {
+			return err
+		}
+	}
+	var (
+		now     = time.Now()
+		changes = make([]string, 0)
+	)
+	for _, course := range watched {
+		change, ok := state.Update(course.ID(), watch.Status{
+			Seats:      course.SeatsOpen(),
+			Waitlisted: course.Enrollment().Waitlisted,
+			Time:       now,
+		})
 		if !ok {
 			continue
 		}
-		openCrns = append(openCrns, crn)
+		changes = append(changes, fmt.Sprintf("%d %s: %s", course.ID(), sectionLabel(course), change))
 	}
-	// return if no open classes
-	if len(openCrns) == 0 {
-		return &internal.Error{Msg: fmt.Sprintf("could not find %v in schedule", crns), Code: 1}
+	if cw.monitor != nil {
+		tracked := make([]watch.Course, len(watched))
+		for i, course := range watched {
+			tracked[i] = watch.Course{
+				CRN:    course.ID(),
+				Name:   sectionLabel(course),
+				Status: state.CRNs[course.ID()],
+			}
+		}
+		cw.monitor.SetCourses(tracked)
 	}
-	msg := "Open crns:\n"
-	for _, crn := range openCrns {
-		msg += fmt.Sprintf("%d\n", crn)
+	// only notify when a course opens, closes,
+	// or crosses the threshold
+	if len(changes) == 0 {
+		if cw.verbose {
+			fmt.Println("no seat changes")
+		}
+		return state.Save()
 	}
+	msg := "Seat changes:\n" + strings.Join(changes, "\n") + "\n"
+	if cw.alerts != nil {
+		err = cw.alerts.Send(msg, now)
+	} else {
+		err = cw.notify(msg)
+	}
+	if err != nil {
+		return err
+	}
+	// the state is saved after the notifications are
+	// sent so that failed notifications are tried again
+	return state.Save()
+}
+
+// notify sends a desktop notification and an sms.
+func (cw *crnWatcher) notify(msg string) error {
 	// desktop notification
 	if config.GetBool("notifications") {
-		if err = beeep.Notify("Found Open Courses", msg, ""); err != nil {
+		if err := beeep.Notify("Course Seats Changed", msg, ""); err != nil {
 			return err
 		}
 	}
 	// sms notification
 	if cw.twilio != nil {
 		to := config.GetString("watch.sms_recipient")
-		_, err = cw.twilio.Send(to, msg)
+		_, err := cw.twilio.Send(to, msg)
 		if err != nil {
 			logrus.WithError(err).Error("could not send sms")
 			return err
 		}
 	}
+	if cw.monitor != nil {
+		cw.monitor.Notified()
+	}
+	return nil
+}
+
+// sectionLabel names a section for
+// notifications, i.e. "CSE 100-02 LAB".
+func sectionLabel(c school.Course) string {
+	subj, num := c.Code()
+	label := subj + " " + num
+	if seq, ok := c.(school.Sequencer); ok && seq.Sequence() != "" {
+		label += "-" + seq.Sequence()
+	}
+	if kind := c.SectionType(); kind != "" {
+		label += " " + kind
+	}
+	return label
+}
+
+// configStrings gets a list of strings from the config.
+func configStrings(key string) []string {
+	switch v := config.Get(key).(type) {
+	case []string:
+		return v
+	case []interface{}:
+		list := make([]string, len(v))
+		for i, s := range v {
+			list[i] = fmt.Sprint(s)
+		}
+		return list
+	}
 	return nil
 }
 
+// openState opens the last known seats of the courses being
+// watched for a school's term. The state is only read again
+// when the school or term changes.
+func (cw *crnWatcher) openState(name string) (*watch.State, error) {
+	dir, err := internal.ConfigSubDir("watch")
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, fmt.Sprintf(
+		"%s-%d-%s.json", strings.ToLower(name), cw.flags.year, strings.ToLower(cw.flags.term)))
+	if cw.state != nil && cw.stateFile == file {
+		return cw.state, nil
+	}
+	state, err := watch.OpenState(file)
+	if err != nil {
+		return nil, err
+	}
+	cw.state, cw.stateFile = state, file
+	return state, nil
+}
+
 func watchFiles() error {
 	basedir := config.GetString("basedir")
 	if basedir == "" {
@@ -289,13 +781,27 @@ func newWatchCmd(sflags *scheduleFlags) *cobra.Command {
 		year         = config.GetInt("watch.year")
 		smsNotify    = config.GetBool("watch.sms_notify")
 		smsRecipient string
+		timeout      = 2 * time.Minute
+		threshold    = config.GetInt("watch.threshold")
+		quietHours   = config.GetString("watch.quiet_hours")
+		jitter       = config.GetFloat("watch.jitter")
+		maxBackoff   = 6 * time.Hour
+		listen       = config.GetString("watch.listen")
 	)
+	if d, err := time.ParseDuration(config.GetString("watch.max_backoff")); err == nil {
+		maxBackoff = d
+	}
 
 	c := &cobra.Command{
-		Use:   "watch",
+		Use:   "watch [crn|course...]",
 		Short: "Watch for availability changes in a list of CRNs",
-		Long: "Watch for availability changes in a list of CRNs." +
-			"",
+		Long: "Watch for availability changes in a list of CRNs or courses.\n" +
+			"Courses are given as 'CSE 100', 'CSE-100-02', or 'MATH 24 LAB' and\n" +
+			"every matching section is watched. Schools that get schedules by\n" +
+			"department, like UC Berkeley, need --subject to watch CRNs.",
+		Example: "" +
+			"$ edu registration watch 30151 30152\n" +
+			"\t$ edu registration watch 'CSE 100' 'MATH-24-02'",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if term != "" {
 				sflags.term = term
@@ -303,60 +809,123 @@ func newWatchCmd(sflags *scheduleFlags) *cobra.Command {
 			if year != 0 {
 				sflags.year = year
 			}
-			basecrns, err := stroiArr(args)
-			if err != nil {
-				return err
+			// arguments that are not crns are courses
+			var (
+				basecrns []int
+				names    []string
+			)
+			for _, arg := range args {
+				if crn, err := strconv.Atoi(arg); err == nil {
+					basecrns = append(basecrns, crn)
+				} else {
+					names = append(names, arg)
+				}
 			}
-			var duration time.Duration
-			duration, err = time.ParseDuration(config.GetString("watch.duration"))
+			duration, err := time.ParseDuration(config.GetString("watch.duration"))
 			if err != nil {
 				return err
 			}
 
 			crnWatch := &crnWatcher{
-				crns:    basecrns,
-				subject: subject,
-				flags:   *sflags,
-				verbose: verbose,
+				crns:      basecrns,
+				names:     names,
+				subject:   subject,
+				flags:     *sflags,
+				verbose:   verbose,
+				ctx:       cmd.Context(),
+				timeout:   timeout,
+				threshold: threshold,
 				twilio: twilio.NewClient(
 					config.GetString("twilio.sid"),
 					config.GetString("twilio.token"),
 				),
 			}
 
+			// the watcher always needs the current seats
+			crnWatch.flags.refresh = true
+			crnWatch.flags.maxAge = 0
 			crnWatch.twilio.SetSender(config.GetString("twilio.number"))
 			if !smsNotify {
 				crnWatch.twilio = nil
 			}
+			var quiet *watch.QuietHours
+			if quietHours != "" {
+				if quiet, err = watch.ParseQuietHours(quietHours); err != nil {
+					return &internal.Error{Msg: err.Error(), Code: 1}
+				}
+			}
+			crnWatch.alerts = watch.NewAlerts(quiet, crnWatch.notify)
 
-			var watches = []watch.Watcher{crnWatch}
-			if config.GetBool("watch.files") {
-				watches = append(watches, watch.WatcherFunc(watchFiles))
-			}
-			for {
-				for _, wt := range watches {
-					go func(wt watch.Watcher) {
-						if err := wt.Watch(); err != nil {
-							log.Printf("Watch Error: %s\n", err.Error())
-						}
-					}(wt)
+			lock, err := lockWatch()
+			if err != nil {
+				return err
+			}
+			defer lock.Unlock()
+
+			sched := watch.NewScheduler(duration)
+			sched.Jitter = jitter
+			sched.MaxBackoff = maxBackoff
+			sched.Alerts = crnWatch.alerts
+			sched.OnError = func(err error) {
+				log.Printf("Watch Error: %s\n", err.Error())
+			}
+			// the config is only reloaded by one goroutine and
+			// never while a watcher is reading it
+			var (
+				configMu sync.RWMutex
+				reloads  = make(chan struct{}, 1)
+			)
+			reload := func() {
+				select {
+				case reloads <- struct{}{}:
+				default:
 				}
-				time.Sleep(duration)
+			}
+			readsConfig := func(fn func() error) watch.Watcher {
+				return watch.WatcherFunc(func() error {
+					configMu.RLock()
+					defer configMu.RUnlock()
+					return fn()
+				})
+			}
+			var checker = readsConfig(crnWatch.Watch)
 
-				// refresh config variables
-				if err = config.ReadConfigFile(); err != nil {
-					log.Printf("could not refresh config during 'watch': %v", err)
+			ctx, stop := context.WithCancel(cmd.Context())
+			defer stop()
+			if listen != "" {
+				crnWatch.monitor = watch.NewMonitor()
+				crnWatch.monitor.Next = sched.Next
+				checker = crnWatch.monitor.Wrap(checker)
+				if err = serveMonitor(ctx, listen, crnWatch.monitor); err != nil {
+					return err
 				}
-				if config.GetString("watch.duration") != "" {
-					newdur, err := time.ParseDuration(config.GetString("watch.duration"))
-					if err != nil {
-						log.Printf("could not refresh duration: %v", err)
-					} else if newdur != 0 {
-						duration = newdur
+			}
+			var watches = []watch.Watcher{checker}
+			if config.GetBool("watch.files") {
+				watches = append(watches, readsConfig(watchFiles))
+			}
+			go func() {
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-reloads:
 					}
+					configMu.Lock()
+					refreshWatchConfig(sched)
+					configMu.Unlock()
+					sched.Trigger()
 				}
+			}()
+			go handleWatchSignals(ctx, stop, reload)
+			if file := config.FileUsed(); file != "" {
+				go watch.PollFile(ctx, file, configPollInterval, reload)
 			}
-			// end RunE
+			err = sched.Run(ctx, watches...)
+			if err == context.Canceled {
+				return nil
+			}
+			return err
 		},
 	}
 
@@ -365,34 +934,108 @@ func newWatchCmd(sflags *scheduleFlags) *cobra.Command {
 	flg.StringVar(&subject, "subject", "", "check the CRNs for a specific subject")
 	flg.BoolVar(&smsNotify, "sms-notify", smsNotify, "notify users when classes are open using sms")
 	flg.StringVar(&smsRecipient, "sms-recipient", "", "number that will be notified via sms (see sms-notify)")
+	flg.IntVar(&threshold, "threshold", threshold, "also notify when the open seats go above or below this number")
+	flg.DurationVar(&timeout, "timeout", timeout, "cancel a check that takes longer than this (0 for no limit)")
+	flg.StringVar(&quietHours, "quiet-hours", quietHours, "hold notifications during these hours and send them together after, i.e. 22:00-07:00")
+	flg.Float64Var(&jitter, "jitter", jitter, "randomly change the time between checks by this fraction of the duration")
+	flg.DurationVar(&maxBackoff, "max-backoff", maxBackoff, "the longest time between checks when they keep failing")
+	flg.StringVar(&listen, "listen", listen, "serve /healthz, /status, and /metrics on this address, i.e. localhost:9090")
 	return c
 }
 
-func courseRow(crs school.Course, title bool, flags scheduleFlags) []string {
-	var (
-		timeStr  = "TBD"
-		activity = "none"
-		days     = ""
-	)
-	if c, ok := crs.(*ucm.Course); ok {
-		if c.Time.Start.Hour() != 0 && c.Time.End.Hour() != 0 {
-			timeStr = fmt.Sprintf("%s-%s",
-				c.Time.Start.Format("3:04pm"),
-				c.Time.End.Format("3:04pm"))
+// serveMonitor serves the monitor's health checks and
+// metrics until the context is done.
+func serveMonitor(ctx context.Context, addr string, m *watch.Monitor) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return &internal.Error{Msg: fmt.Sprintf("could not listen on %s: %v", addr, err), Code: 1}
+	}
+	srv := &http.Server{Handler: m.Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+	go func() {
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+			log.Printf("watch server stopped: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// configPollInterval is how often the watch
+// command checks the config file for changes.
+var configPollInterval = 5 * time.Second
+
+// lockWatch takes the watch lock file so that only
+// one watch command sends notifications at a time.
+func lockWatch() (*watch.Lock, error) {
+	dir, err := internal.ConfigSubDir("watch")
+	if err != nil {
+		return nil, err
+	}
+	lock, err := watch.AcquireLock(filepath.Join(dir, "watch.lock"))
+	if errors.Is(err, watch.ErrLocked) {
+		return nil, &internal.Error{
+			Msg:  fmt.Sprintf("another watch is already running (%v)", err),
+			Code: 1,
 		}
-		days = strjoin(c.Days, ",")
-		activity = c.Activity
 	}
+	return lock, err
+}
 
-	seats := crs.SeatsOpen()
-	var open = strconv.Itoa(seats)
-	if !flags.NoColor {
-		if seats <= 0 {
-			open = term.Red(open)
-		} else {
-			open = term.Green(open)
+// handleWatchSignals stops the watch on SIGINT or SIGTERM and
+// reloads the config on SIGHUP. A second SIGINT or SIGTERM
+// will exit without waiting for the running checks.
+func handleWatchSignals(ctx context.Context, stop func(), reload func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigs)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-sigs:
+			if sig == syscall.SIGHUP {
+				log.Println("reloading config")
+				reload()
+				continue
+			}
+			log.Printf("received %v, waiting for running checks to finish\n", sig)
+			stop()
+			return
 		}
 	}
+}
+
+// refreshWatchConfig reads the config file again so that
+// the watch settings can be changed while it is running.
+func refreshWatchConfig(sched *watch.Scheduler) {
+	if err := config.ReadConfigFile(); err != nil {
+		log.Printf("could not refresh config during 'watch': %v", err)
+	}
+	if config.GetString("watch.duration") != "" {
+		newdur, err := time.ParseDuration(config.GetString("watch.duration"))
+		if err != nil {
+			log.Printf("could not refresh duration: %v", err)
+		} else if newdur != 0 {
+			sched.SetInterval(newdur)
+		}
+	}
+}
+
+func courseRow(crs school.Course, title bool, flags scheduleFlags) []string {
+	var (
+		timeStr, days = meetingTimes(crs.MeetingTimes())
+		activity      = crs.SectionType()
+	)
+	if activity == "" {
+		activity = "none"
+	}
+
+	open := seatStr(crs.SeatsOpen(), flags.NoColor)
 
 	if title {
 		return []string{
@@ -415,10 +1058,29 @@ func courseRow(crs school.Course, title bool, flags scheduleFlags) []string {
 	}
 }
 
-var mustAlsoRegex = regexp.MustCompile(`Must Also.*$`)
+// meetingTimes returns the times and days of each meeting with
+// one line per meeting.
+func meetingTimes(meetings []school.Meeting) (times, days string) {
+	var (
+		timeList = make([]string, len(meetings))
+		dayList  = make([]string, len(meetings))
+	)
+	for i, m := range meetings {
+		timeList[i] = "TBD"
+		if !m.TBD() {
+			timeList[i] = fmt.Sprintf("%s-%s",
+				m.Start.Format("3:04pm"),
+				m.End.Format("3:04pm"))
+		}
+		dayList[i] = strjoin(m.Days, ",")
+	}
+	if len(meetings) == 0 {
+		return "TBD", ""
+	}
+	return strings.Join(timeList, "\n"), strings.Join(dayList, "\n")
+}
 
 func cleanTitle(title string) string {
-	title = mustAlsoRegex.ReplaceAllString(title, "")
 	title = strings.Replace(title, "Class is fully online", ": Class is fully online", -1)
 	if len(title) > 175 {
 		title = title[:175]
@@ -426,6 +1088,14 @@ func cleanTitle(title string) string {
 	return title
 }
 
+func intsToStrings(ints []int) []string {
+	strs := make([]string, len(ints))
+	for i, n := range ints {
+		strs[i] = strconv.Itoa(n)
+	}
+	return strs
+}
+
 func stroiArr(arr []string) (ints []int, err error) {
 	ints = make([]int, len(arr))
 	for i, n := range arr {
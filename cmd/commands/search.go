@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/edu/cmd/internal"
+	"github.com/harrybrwn/edu/school/ucmerced/ucm"
+	"github.com/spf13/cobra"
+)
+
+func newSearchCmd(sflags *scheduleFlags) *cobra.Command {
+	var (
+		subject string
+		refresh bool
+		opts    = ucm.FetchOptions{Workers: 4, Interval: 250 * time.Millisecond}
+	)
+	c := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search course titles and descriptions",
+		Long: "Search course titles and descriptions.\n\n" +
+			"Course descriptions are downloaded the first time they are\n" +
+			"searched and saved in the config directory.",
+		Example: "$ edu registration search \"machine learning\"\n" +
+			"\t$ edu reg search --subject=cse graph",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, p, err := sflags.bannerSite("search")
+			if err != nil {
+				return err
+			}
+			schedule, err := sflags.bannerSchedule(cmd.Context(), "search", subject, sflags.open)
+			if err != nil {
+				return err
+			}
+			file, err := descriptionsFile(p.Name, sflags.year, sflags.term)
+			if err != nil {
+				return err
+			}
+			descs := make(ucm.Descriptions)
+			if !refresh {
+				if descs, err = readDescriptions(file); err != nil {
+					return err
+				}
+			}
+
+			courses := schedule.Ordered()
+			opts.Progress = func(done, total int) {
+				fmt.Fprintf(os.Stderr, "\rdownloading descriptions %d/%d", done, total)
+				if done == total {
+					fmt.Fprintln(os.Stderr)
+				}
+			}
+			// an interrupt stops the downloads but the
+			// descriptions that were downloaded are saved
+			ctx, stop := context.WithCancel(cmd.Context())
+			sigs := make(chan os.Signal, 1)
+			signal.Notify(sigs, os.Interrupt)
+			go func() {
+				select {
+				case <-sigs:
+					stop()
+				case <-ctx.Done():
+				}
+			}()
+			err = descs.Fetch(ctx, courses, opts)
+			signal.Stop(sigs)
+			stop()
+			if e := writeDescriptions(file, descs); e != nil {
+				return e
+			}
+			if errors.Is(err, context.Canceled) {
+				fmt.Fprintln(os.Stderr)
+				return &internal.Error{Msg: "stopped downloading descriptions", Code: 1}
+			} else if err != nil {
+				// still search whatever was downloaded
+				log.Printf("could not get some descriptions: %v\n", err)
+				fmt.Fprintln(os.Stderr, "Warning: some descriptions could not be downloaded")
+			}
+
+			results := descs.Search(courses, strings.Join(args, " "))
+			if len(results) == 0 {
+				return &internal.Error{Msg: "no matches", Code: 1}
+			}
+			tab := internal.NewTable(cmd.OutOrStdout())
+			internal.SetTableHeader(tab, []string{"code", "title", "prerequisites", "corequisites"}, !sflags.NoColor)
+			tab.SetAutoWrapText(false)
+			for _, c := range results {
+				var pre, co string
+				if d, ok := descs[c.CourseKey()]; ok {
+					pre, co = d.Prerequisites, d.Corequisites
+				}
+				tab.Append([]string{c.CourseKey(), cleanTitle(c.Title), pre, co})
+			}
+			tab.Render()
+			return nil
+		},
+	}
+	flags := c.Flags()
+	flags.StringVar(&subject, "subject", "", "only search one subject")
+	flags.BoolVar(&refresh, "refresh-descriptions", refresh, "download all the descriptions again")
+	flags.IntVar(&opts.Workers, "workers", opts.Workers, "number of concurrent downloads")
+	flags.DurationVar(&opts.Interval, "interval", opts.Interval, "minimum time between requests")
+	return c
+}
+
+// descriptionsFile returns the file that a school's
+// course descriptions are saved in for a term.
+func descriptionsFile(name string, year int, term string) (string, error) {
+	dir, err := internal.ConfigSubDir("descriptions")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf(
+		"%s-%d-%s.json", strings.ToLower(name), year, strings.ToLower(term))), nil
+}
+
+func readDescriptions(file string) (ucm.Descriptions, error) {
+	descs := make(ucm.Descriptions)
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return descs, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return descs, json.Unmarshal(b, &descs)
+}
+
+func writeDescriptions(file string, descs ucm.Descriptions) error {
+	b, err := json.Marshal(descs)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
@@ -0,0 +1,183 @@
+// Package history stores the seat counts of courses over time.
+package history
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Sample is the enrollment of a course at one point in time.
+type Sample struct {
+	Time       time.Time
+	Capacity   int
+	Enrolled   int
+	Seats      int
+	Waitlisted int
+}
+
+// Store is a file based store of samples. Each course
+// has its own csv file in the store's directory.
+type Store struct {
+	dir string
+}
+
+// Open will open a store in a directory and create
+// the directory if it does not exist.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the store's directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Add will add a sample to the course's history.
+func (s *Store) Add(crn int, smp Sample) error {
+	f, err := os.OpenFile(s.file(crn), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	err = w.Write([]string{
+		smp.Time.Format(time.RFC3339),
+		strconv.Itoa(smp.Capacity),
+		strconv.Itoa(smp.Enrolled),
+		strconv.Itoa(smp.Seats),
+		strconv.Itoa(smp.Waitlisted),
+	})
+	w.Flush()
+	if err == nil {
+		err = w.Error()
+	}
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// Series will get all the samples for a course.
+func (s *Store) Series(crn int) (Series, error) {
+	f, err := os.Open(s.file(crn))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readSeries(f)
+}
+
+func (s *Store) file(crn int) string {
+	return filepath.Join(s.dir, strconv.Itoa(crn)+".csv")
+}
+
+// sampleFields is the number of fields in a record.
+const sampleFields = 5
+
+func readSeries(r io.Reader) (Series, error) {
+	var (
+		series = make(Series, 0, 64)
+		rd     = csv.NewReader(bufio.NewReader(r))
+	)
+	rd.FieldsPerRecord = sampleFields
+	for {
+		rec, err := rd.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		smp, err := parseSample(rec)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, smp)
+	}
+	return series, nil
+}
+
+func parseSample(rec []string) (smp Sample, err error) {
+	if len(rec) != sampleFields {
+		return smp, fmt.Errorf("bad history record: wrong number of fields %d", len(rec))
+	}
+	if smp.Time, err = time.Parse(time.RFC3339, rec[0]); err != nil {
+		return
+	}
+	var nums [sampleFields - 1]int
+	for i := 1; i < len(rec); i++ {
+		if nums[i-1], err = strconv.Atoi(rec[i]); err != nil {
+			return smp, fmt.Errorf("bad history record: %w", err)
+		}
+	}
+	smp.Capacity, smp.Enrolled, smp.Seats, smp.Waitlisted = nums[0], nums[1], nums[2], nums[3]
+	return smp, nil
+}
+
+// Series is a list of samples in the order that they were added.
+type Series []Sample
+
+// Seats returns a list of the open seats for each sample.
+func (s Series) Seats() []int {
+	seats := make([]int, len(s))
+	for i, smp := range s {
+		seats[i] = smp.Seats
+	}
+	return seats
+}
+
+// Period is a span of time when a course had open seats.
+type Period struct {
+	Start, End time.Time
+	// MaxSeats is the most seats that were open
+	// during the period.
+	MaxSeats int
+	// Ongoing is true when the seats were still open
+	// at the last sample.
+	Ongoing bool
+}
+
+// Duration returns the length of the period.
+func (p *Period) Duration() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// OpenPeriods will find all the periods of time when
+// the course had open seats.
+func (s Series) OpenPeriods() []Period {
+	var (
+		periods = make([]Period, 0)
+		cur     *Period
+	)
+	for _, smp := range s {
+		if smp.Seats > 0 {
+			if cur == nil {
+				cur = &Period{Start: smp.Time}
+			}
+			if smp.Seats > cur.MaxSeats {
+				cur.MaxSeats = smp.Seats
+			}
+			cur.End = smp.Time
+			continue
+		}
+		if cur != nil {
+			// the seats were taken some time
+			// before this sample
+			cur.End = smp.Time
+			periods = append(periods, *cur)
+			cur = nil
+		}
+	}
+	if cur != nil {
+		cur.Ongoing = true
+		periods = append(periods, *cur)
+	}
+	return periods
+}
@@ -0,0 +1,78 @@
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "edu-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2021, time.January, 4, 8, 0, 0, 0, time.UTC)
+	seats := []int{0, 2, 3, 0, 0, 1}
+	for i, n := range seats {
+		err = store.Add(30151, Sample{
+			Time:       start.Add(time.Duration(i) * time.Hour),
+			Capacity:   30,
+			Enrolled:   30 - n,
+			Seats:      n,
+			Waitlisted: i,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	series, err := store.Series(30151)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != len(seats) {
+		t.Fatalf("wrong number of samples: got %d; want %d", len(series), len(seats))
+	}
+	for i, n := range series.Seats() {
+		if n != seats[i] {
+			t.Errorf("wrong seats: got %d; want %d", n, seats[i])
+		}
+	}
+	if !series[0].Time.Equal(start) || series[2].Enrolled != 27 || series[4].Waitlisted != 4 {
+		t.Error("sample was not stored correctly")
+	}
+
+	periods := series.OpenPeriods()
+	if len(periods) != 2 {
+		t.Fatalf("wrong number of periods: got %d; want 2", len(periods))
+	}
+	if periods[0].Duration() != 2*time.Hour || periods[0].MaxSeats != 3 || periods[0].Ongoing {
+		t.Errorf("wrong first period: %+v", periods[0])
+	}
+	if !periods[1].Ongoing || periods[1].Duration() != 0 {
+		t.Errorf("wrong last period: %+v", periods[1])
+	}
+
+	if _, err = store.Series(1); !os.IsNotExist(err) {
+		t.Error("expected a not exist error for an unknown crn")
+	}
+}
+
+func TestReadBadSeries(t *testing.T) {
+	for _, rec := range []string{
+		"2021-01-04T08:00:00Z,30,28,2\n",
+		"2021-01-04T08:00:00Z,30\n",
+		"2021-01-04T08:00:00Z,30,28,2,0,1\n",
+		"2021-01-04T08:00:00Z,30,28,two,0\n",
+	} {
+		if _, err := readSeries(strings.NewReader(rec)); err == nil {
+			t.Errorf("expected an error for %q", rec)
+		}
+	}
+}
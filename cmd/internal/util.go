@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/harrybrwn/config"
 	"github.com/harrybrwn/go-canvas"
 	table "github.com/olekukonko/tablewriter"
 )
@@ -41,6 +43,17 @@ func Mkdir(dir string) error {
 	return nil
 }
 
+// ConfigSubDir returns a directory inside the config
+// directory and creates it if it does not exist.
+func ConfigSubDir(name string) (string, error) {
+	base := config.DirUsed()
+	if base == "" {
+		return "", errors.New("could not find config directory")
+	}
+	dir := filepath.Join(base, name)
+	return dir, Mkdir(dir)
+}
+
 // GetCourses gets all the courses
 func GetCourses(all bool, opts ...canvas.Option) ([]*canvas.Course, error) {
 	if !all {
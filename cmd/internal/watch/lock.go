@@ -0,0 +1,96 @@
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrLocked is returned when another process holds a lock.
+var ErrLocked = errors.New("lock is held by another process")
+
+// lockGrace is how long a lock file without a pid is
+// held, its owner may not have written the pid yet.
+const lockGrace = 10 * time.Second
+
+// Lock is a lock file that holds the id of the
+// process that created it.
+type Lock struct {
+	file string
+}
+
+// AcquireLock creates a lock file. If the file already exists and
+// the process that created it is still running then ErrLocked is
+// returned, otherwise the old lock is taken over. A lock file
+// without a pid is only taken over once it is older than a few
+// seconds so that a lock that is being created is not removed.
+func AcquireLock(file string) (*Lock, error) {
+	for i := 0; i < 2; i++ {
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = fmt.Fprintf(f, "%d\n", os.Getpid())
+			if e := f.Close(); err == nil {
+				err = e
+			}
+			if err != nil {
+				os.Remove(file)
+				return nil, err
+			}
+			return &Lock{file: file}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		pid, err := lockOwner(file)
+		if os.IsNotExist(err) {
+			continue // unlocked since the create
+		} else if err != nil {
+			info, e := os.Stat(file)
+			if e == nil && time.Since(info.ModTime()) < lockGrace {
+				return nil, fmt.Errorf("%w: %s is being created", ErrLocked, file)
+			}
+		} else if processRunning(pid) {
+			return nil, fmt.Errorf("%w: pid %d has %s", ErrLocked, pid, file)
+		}
+		// the lock was left behind by a process that has stopped
+		if err = os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrLocked, file)
+}
+
+// Unlock removes the lock file.
+func (l *Lock) Unlock() error {
+	return os.Remove(l.file)
+}
+
+func lockOwner(file string) (int, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// FindProcess only succeeds for running processes on windows
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,62 @@
+package watch
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "edu-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "watch.lock")
+
+	l, err := AcquireLock(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = AcquireLock(file); !errors.Is(err, ErrLocked) {
+		t.Fatalf("a second lock should fail, got %v", err)
+	}
+	if err = l.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if l, err = AcquireLock(file); err != nil {
+		t.Fatalf("should lock after unlocking: %v", err)
+	}
+	l.Unlock()
+
+	// a lock left by a process that is no longer running
+	if err = ioutil.WriteFile(file, []byte("1073741824\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if l, err = AcquireLock(file); err != nil {
+		t.Fatalf("should take over a stale lock: %v", err)
+	}
+	if pid, _ := lockOwner(file); pid != os.Getpid() {
+		t.Errorf("wrong pid in lock file: %d", pid)
+	}
+	l.Unlock()
+
+	// a lock that is still being created
+	if err = ioutil.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = AcquireLock(file); !errors.Is(err, ErrLocked) {
+		t.Fatalf("an empty lock file should be held, got %v", err)
+	}
+	old := time.Now().Add(-2 * lockGrace)
+	if err = os.Chtimes(file, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if l, err = AcquireLock(file); err != nil {
+		t.Fatalf("should take over an old empty lock: %v", err)
+	}
+	l.Unlock()
+}
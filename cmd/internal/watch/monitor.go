@@ -0,0 +1,212 @@
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Course is a course being watched and its last known seats.
+type Course struct {
+	CRN  int    `json:"crn"`
+	Name string `json:"name"`
+	Status
+}
+
+// Monitor keeps track of how the watch is doing so
+// that it can be served over http for health checks
+// and metrics.
+type Monitor struct {
+	// Next returns the next time the watch will
+	// run, may be nil.
+	Next func() time.Time
+
+	mu            sync.Mutex
+	started       time.Time
+	lastRun       time.Time
+	lastSuccess   time.Time
+	lastDuration  time.Duration
+	lastErr       error
+	runs          int
+	failures      int
+	durations     time.Duration
+	notifications int
+	courses       []Course
+	now           func() time.Time
+}
+
+// NewMonitor creates a new monitor.
+func NewMonitor() *Monitor {
+	return &Monitor{started: time.Now(), now: time.Now}
+}
+
+// Wrap returns a watcher that records each
+// time the watcher is run.
+func (m *Monitor) Wrap(w Watcher) Watcher {
+	return WatcherFunc(func() error {
+		start := m.now()
+		err := w.Watch()
+		m.record(start, m.now().Sub(start), err)
+		return err
+	})
+}
+
+func (m *Monitor) record(start time.Time, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs++
+	m.durations += d
+	m.lastRun = start
+	m.lastDuration = d
+	m.lastErr = err
+	if err != nil {
+		m.failures++
+	} else {
+		m.lastSuccess = start
+	}
+}
+
+// SetCourses replaces the courses being watched.
+func (m *Monitor) SetCourses(courses []Course) {
+	sorted := make([]Course, len(courses))
+	copy(sorted, courses)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].CRN < sorted[j].CRN })
+	m.mu.Lock()
+	m.courses = sorted
+	m.mu.Unlock()
+}
+
+// Notified counts a notification that was sent.
+func (m *Monitor) Notified() {
+	m.mu.Lock()
+	m.notifications++
+	m.mu.Unlock()
+}
+
+// Handler returns an http handler that serves /healthz,
+// /status, and /metrics.
+func (m *Monitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", m.healthz)
+	mux.HandleFunc("/status", m.status)
+	mux.HandleFunc("/metrics", m.metrics)
+	return mux
+}
+
+// healthz fails if the last run failed.
+func (m *Monitor) healthz(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	err := m.lastErr
+	m.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "last check failed: %v\n", err)
+		return
+	}
+	io.WriteString(w, "ok\n")
+}
+
+type status struct {
+	Started     time.Time  `json:"started"`
+	LastRun     *time.Time `json:"last_run"`
+	LastSuccess *time.Time `json:"last_success"`
+	LastError   string     `json:"last_error,omitempty"`
+	NextRun     *time.Time `json:"next_run"`
+	Runs        int        `json:"runs"`
+	Failures    int        `json:"failures"`
+	Courses     []Course   `json:"courses"`
+}
+
+func (m *Monitor) status(w http.ResponseWriter, r *http.Request) {
+	var next time.Time
+	if m.Next != nil {
+		next = m.Next()
+	}
+	m.mu.Lock()
+	st := status{
+		Started:     m.started,
+		LastRun:     timePtr(m.lastRun),
+		LastSuccess: timePtr(m.lastSuccess),
+		NextRun:     timePtr(next),
+		Runs:        m.runs,
+		Failures:    m.failures,
+		Courses:     m.courses,
+	}
+	if m.lastErr != nil {
+		st.LastError = m.lastErr.Error()
+	}
+	if st.Courses == nil {
+		st.Courses = []Course{}
+	}
+	m.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(&st)
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// metrics writes the prometheus text format.
+func (m *Monitor) metrics(w http.ResponseWriter, r *http.Request) {
+	var next time.Time
+	if m.Next != nil {
+		next = m.Next()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	metric(w, "edu_watch_scrape_duration_seconds", "summary", "Time spent checking the schedule.")
+	fmt.Fprintf(w, "edu_watch_scrape_duration_seconds_sum %g\n", m.durations.Seconds())
+	fmt.Fprintf(w, "edu_watch_scrape_duration_seconds_count %d\n", m.runs)
+	metric(w, "edu_watch_last_scrape_duration_seconds", "gauge", "Time spent on the last check.")
+	fmt.Fprintf(w, "edu_watch_last_scrape_duration_seconds %g\n", m.lastDuration.Seconds())
+	metric(w, "edu_watch_scrape_failures_total", "counter", "Number of checks that failed.")
+	fmt.Fprintf(w, "edu_watch_scrape_failures_total %d\n", m.failures)
+	metric(w, "edu_watch_last_scrape_timestamp_seconds", "gauge", "Time of the last check.")
+	fmt.Fprintf(w, "edu_watch_last_scrape_timestamp_seconds %d\n", unix(m.lastRun))
+	metric(w, "edu_watch_last_success_timestamp_seconds", "gauge", "Time of the last check that did not fail.")
+	fmt.Fprintf(w, "edu_watch_last_success_timestamp_seconds %d\n", unix(m.lastSuccess))
+	metric(w, "edu_watch_next_scrape_timestamp_seconds", "gauge", "Time of the next check.")
+	fmt.Fprintf(w, "edu_watch_next_scrape_timestamp_seconds %d\n", unix(next))
+	metric(w, "edu_watch_notifications_total", "counter", "Number of notifications sent.")
+	fmt.Fprintf(w, "edu_watch_notifications_total %d\n", m.notifications)
+
+	metric(w, "edu_watch_seats", "gauge", "Open seats of a watched course.")
+	for _, c := range m.courses {
+		fmt.Fprintf(w, "edu_watch_seats{%s} %d\n", courseLabels(c), c.Seats)
+	}
+	metric(w, "edu_watch_waitlisted", "gauge", "Waitlisted students of a watched course.")
+	for _, c := range m.courses {
+		fmt.Fprintf(w, "edu_watch_waitlisted{%s} %d\n", courseLabels(c), c.Waitlisted)
+	}
+}
+
+func metric(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func courseLabels(c Course) string {
+	return fmt.Sprintf(`crn="%d",course="%s"`, c.CRN, labelEscaper.Replace(c.Name))
+}
+
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,94 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonitor(t *testing.T) {
+	var (
+		m    = NewMonitor()
+		fail = true
+		now  = time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+	)
+	m.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	m.Next = func() time.Time { return now.Add(time.Hour) }
+	w := m.Wrap(WatcherFunc(func() error {
+		if fail {
+			return errors.New("site is down")
+		}
+		return nil
+	}))
+	srv := httptest.NewServer(m.Handler())
+	defer srv.Close()
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(b)
+	}
+
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Errorf("should be healthy before the first run, got %d", code)
+	}
+	w.Watch()
+	if code, body := get("/healthz"); code != http.StatusServiceUnavailable || !strings.Contains(body, "site is down") {
+		t.Errorf("should be unhealthy after a failure, got %d %q", code, body)
+	}
+	fail = false
+	w.Watch()
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Errorf("should be healthy after a success, got %d", code)
+	}
+	m.SetCourses([]Course{
+		{CRN: 2, Name: `CSE 100-02 "LAB"`, Status: Status{Seats: 0, Waitlisted: 3}},
+		{CRN: 1, Name: "CSE 100-01 LECT", Status: Status{Seats: 5}},
+	})
+	m.Notified()
+
+	_, body := get("/status")
+	var st status
+	if err := json.Unmarshal([]byte(body), &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Runs != 2 || st.Failures != 1 || st.LastError != "" {
+		t.Errorf("wrong status: %+v", st)
+	}
+	if st.NextRun == nil || st.LastRun == nil || !st.NextRun.After(*st.LastRun) {
+		t.Errorf("wrong run times: %s", body)
+	}
+	if len(st.Courses) != 2 || st.Courses[0].CRN != 1 || st.Courses[1].Waitlisted != 3 {
+		t.Errorf("wrong courses: %+v", st.Courses)
+	}
+
+	_, body = get("/metrics")
+	for _, line := range []string{
+		"# TYPE edu_watch_scrape_duration_seconds summary",
+		"edu_watch_scrape_duration_seconds_sum 2",
+		"edu_watch_scrape_duration_seconds_count 2",
+		"edu_watch_scrape_failures_total 1",
+		"edu_watch_notifications_total 1",
+		`edu_watch_seats{crn="1",course="CSE 100-01 LECT"} 5`,
+		`edu_watch_waitlisted{crn="2",course="CSE 100-02 \"LAB\""} 3`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics should have %q:\n%s", line, body)
+		}
+	}
+}
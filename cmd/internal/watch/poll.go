@@ -0,0 +1,31 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// PollFile calls fn every time the modification time or size of
+// a file changes. The file is checked every interval until the
+// context is done.
+func PollFile(ctx context.Context, file string, interval time.Duration, fn func()) {
+	last, _ := os.Stat(file)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		if last == nil || !info.ModTime().Equal(last.ModTime()) || info.Size() != last.Size() {
+			last = info
+			fn()
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package watch
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QuietHours is a daily period when alerts are not sent. The
+// period wraps around midnight if the end is before the start.
+type QuietHours struct {
+	// Start and End are minutes after midnight.
+	Start, End int
+}
+
+// ParseQuietHours parses a period like "22:00-07:00".
+func ParseQuietHours(s string) (*QuietHours, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("quiet hours %q should look like 22:00-07:00", s)
+	}
+	var (
+		q   QuietHours
+		err error
+	)
+	if q.Start, err = parseClock(parts[0]); err != nil {
+		return nil, err
+	}
+	if q.End, err = parseClock(parts[1]); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("bad time %q, use a time like 22:00", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains returns true if t is during quiet hours.
+func (q *QuietHours) Contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if q.Start <= q.End {
+		return m >= q.Start && m < q.End
+	}
+	return m >= q.Start || m < q.End
+}
+
+func (q *QuietHours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", q.Start/60, q.Start%60, q.End/60, q.End%60)
+}
+
+// Alerts sends alerts unless it is quiet hours. Alerts
+// that come in during quiet hours are queued and sent
+// together once quiet hours are over.
+type Alerts struct {
+	quiet *QuietHours
+	send  func(msg string) error
+
+	mu    sync.Mutex
+	queue []string
+	// state is where the queue is saved, may be nil.
+	state *State
+}
+
+// NewAlerts creates alerts that are sent with the send
+// function. Quiet hours may be nil.
+func NewAlerts(quiet *QuietHours, send func(string) error) *Alerts {
+	return &Alerts{quiet: quiet, send: send}
+}
+
+// Send will send an alert or queue it if it is quiet hours.
+func (a *Alerts) Send(msg string, now time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.quiet != nil && a.quiet.Contains(now) {
+		a.queue = append(a.queue, msg)
+		return a.save()
+	}
+	return a.send(msg)
+}
+
+// Persist saves the queued alerts with a state so that they are
+// not lost when the watch is restarted. Alerts that were queued
+// in the state are sent with the rest. If the alerts were saved
+// with a different state they are moved to the new one.
+func (a *Alerts) Persist(s *State) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.state == s {
+		return nil
+	}
+	if a.state != nil {
+		a.state.setQueue(nil)
+		if err := a.state.Save(); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	a.queue = append(a.queue, s.Queue...)
+	s.mu.Unlock()
+	a.state = s
+	return a.save()
+}
+
+func (a *Alerts) save() error {
+	if a.state == nil {
+		return nil
+	}
+	a.state.setQueue(a.queue)
+	return a.state.Save()
+}
+
+// Queued returns the number of alerts waiting to be sent.
+func (a *Alerts) Queued() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.queue)
+}
+
+// Flush sends all the queued alerts as one
+// alert if it is not quiet hours.
+func (a *Alerts) Flush(now time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.queue) == 0 || (a.quiet != nil && a.quiet.Contains(now)) {
+		return nil
+	}
+	if err := a.send(strings.Join(a.queue, "\n")); err != nil {
+		return err
+	}
+	a.queue = nil
+	return a.save()
+}
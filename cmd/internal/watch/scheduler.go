@@ -0,0 +1,225 @@
+package watch
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Scheduler runs watchers on an interval. Each watcher is run
+// again an interval after it finishes with some random jitter
+// so that checks do not line up. A watcher that keeps failing
+// is backed off exponentially up to MaxBackoff.
+type Scheduler struct {
+	// Jitter is the fraction of the interval that
+	// is randomly added or removed from each wait.
+	Jitter float64
+	// MaxBackoff is the longest wait for a watcher that
+	// keeps failing. The interval is used if it is longer.
+	MaxBackoff time.Duration
+	// Tick is how often the wall clock is checked. Timers
+	// stop while the computer is suspended so the wall clock
+	// is used to catch up on checks that were missed.
+	Tick time.Duration
+	// Alerts are flushed on every tick, may be nil.
+	Alerts *Alerts
+	// OnError is called when a watcher fails, may be nil.
+	OnError func(error)
+
+	mu       sync.Mutex
+	interval time.Duration
+	now      func() time.Time
+	rand     *rand.Rand
+	trigger  chan struct{}
+	next     time.Time
+}
+
+// NewScheduler creates a scheduler that runs
+// watchers every interval.
+func NewScheduler(interval time.Duration) *Scheduler {
+	return &Scheduler{
+		Jitter:     0.1,
+		MaxBackoff: 6 * time.Hour,
+		Tick:       time.Minute,
+		interval:   interval,
+		now:        time.Now,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		trigger:    make(chan struct{}, 1),
+	}
+}
+
+// Trigger will run every watcher that is not
+// already running without waiting for the interval.
+func (s *Scheduler) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// SetInterval changes the interval, it is safe to
+// call while the scheduler is running.
+func (s *Scheduler) SetInterval(d time.Duration) {
+	s.mu.Lock()
+	s.interval = d
+	s.mu.Unlock()
+}
+
+// Interval returns the time between runs.
+func (s *Scheduler) Interval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interval
+}
+
+// Next returns the next time a watcher will be run. It is
+// zero if every watcher is running or the scheduler is not.
+func (s *Scheduler) Next() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next
+}
+
+type job struct {
+	watcher  Watcher
+	next     time.Time
+	failures int
+	running  bool
+	// triggered is set when the scheduler is triggered
+	// while the watcher is running so that it runs again.
+	triggered bool
+}
+
+type result struct {
+	job *job
+	err error
+}
+
+// Run will run the watchers until the context is done. Every
+// watcher is run right away and a watcher is never run again
+// while it is still running. Once the context is done Run
+// waits for the running watchers to finish before returning.
+func (s *Scheduler) Run(ctx context.Context, watchers ...Watcher) error {
+	var (
+		jobs    = make([]*job, len(watchers))
+		results = make(chan result, len(watchers))
+		now     = s.wallclock()
+	)
+	for i, w := range watchers {
+		jobs[i] = &job{watcher: w, next: now}
+	}
+	for {
+		now = s.wallclock()
+		var (
+			wait = s.Tick
+			next time.Time
+		)
+		for _, j := range jobs {
+			if j.running {
+				continue
+			}
+			if !now.Before(j.next) {
+				j.running = true
+				go func(j *job) {
+					results <- result{job: j, err: j.watcher.Watch()}
+				}(j)
+				continue
+			}
+			if next.IsZero() || j.next.Before(next) {
+				next = j.next
+			}
+			if d := j.next.Sub(now); d < wait {
+				wait = d
+			}
+		}
+		s.mu.Lock()
+		s.next = next
+		s.mu.Unlock()
+		if s.Alerts != nil {
+			if err := s.Alerts.Flush(now); err != nil && s.OnError != nil {
+				s.OnError(err)
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			for _, j := range jobs {
+				if j.running {
+					s.finish(<-results)
+				}
+			}
+			s.mu.Lock()
+			s.next = time.Time{}
+			s.mu.Unlock()
+			return ctx.Err()
+		case <-s.trigger:
+			timer.Stop()
+			for _, j := range jobs {
+				if j.running {
+					j.triggered = true
+				} else {
+					j.next = now
+				}
+			}
+		case r := <-results:
+			timer.Stop()
+			s.finish(r)
+		case <-timer.C:
+		}
+	}
+}
+
+// finish records the result of a watcher and sets the next
+// time it should be run. A watcher that was triggered while
+// it was running is run again right away.
+func (s *Scheduler) finish(r result) {
+	r.job.running = false
+	if r.err != nil {
+		r.job.failures++
+		if s.OnError != nil {
+			s.OnError(r.err)
+		}
+	} else {
+		r.job.failures = 0
+	}
+	if r.job.triggered {
+		r.job.triggered = false
+		r.job.next = s.wallclock()
+		return
+	}
+	r.job.next = s.wallclock().Add(s.delay(r.job.failures))
+}
+
+// delay returns the wait before the next run given
+// the number of times in a row that a watcher failed.
+func (s *Scheduler) delay(failures int) time.Duration {
+	interval := s.Interval()
+	d := interval
+	if failures > 0 {
+		limit := s.MaxBackoff
+		if limit < interval {
+			limit = interval
+		}
+		for i := 0; i < failures && d < limit; i++ {
+			d *= 2
+		}
+		if d > limit {
+			d = limit
+		}
+	}
+	if s.Jitter > 0 {
+		s.mu.Lock()
+		f := s.rand.Float64()*2 - 1
+		s.mu.Unlock()
+		d += time.Duration(float64(d) * s.Jitter * f)
+	}
+	return d
+}
+
+// wallclock returns the time without the monotonic clock
+// reading so that time spent suspended is counted.
+func (s *Scheduler) wallclock() time.Time {
+	return s.now().Round(0)
+}
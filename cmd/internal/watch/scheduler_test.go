@@ -0,0 +1,317 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDelay(t *testing.T) {
+	s := NewScheduler(time.Hour)
+	s.Jitter = 0
+	s.MaxBackoff = 6 * time.Hour
+	for failures, want := range []time.Duration{
+		time.Hour, 2 * time.Hour, 4 * time.Hour, 6 * time.Hour, 6 * time.Hour,
+	} {
+		if d := s.delay(failures); d != want {
+			t.Errorf("%d failures: got %v; want %v", failures, d, want)
+		}
+	}
+	s.SetInterval(12 * time.Hour)
+	if d := s.delay(3); d != 12*time.Hour {
+		t.Errorf("backoff should never be less than the interval, got %v", d)
+	}
+
+	s.SetInterval(time.Hour)
+	s.Jitter = 0.1
+	for i := 0; i < 100; i++ {
+		d := s.delay(0)
+		if d < 54*time.Minute || d > 66*time.Minute {
+			t.Fatalf("jitter out of range: %v", d)
+		}
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	var (
+		mu              sync.Mutex
+		good, bad, errs int
+		running         int
+		overlap         bool
+		s               = NewScheduler(20 * time.Millisecond)
+		ctx, cancel     = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	)
+	defer cancel()
+	s.Jitter = 0
+	s.MaxBackoff = time.Hour
+	s.Tick = 5 * time.Millisecond
+	s.OnError = func(error) { mu.Lock(); errs++; mu.Unlock() }
+	goodW := WatcherFunc(func() error {
+		mu.Lock()
+		good++
+		running++
+		overlap = overlap || running > 1
+		mu.Unlock()
+		time.Sleep(30 * time.Millisecond) // longer than the interval
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	badW := WatcherFunc(func() error {
+		mu.Lock()
+		bad++
+		mu.Unlock()
+		return errors.New("site is down")
+	})
+	if err := s.Run(ctx, goodW, badW); err != context.DeadlineExceeded {
+		t.Fatalf("expected the context deadline, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if overlap {
+		t.Error("a watcher should not run while it is still running")
+	}
+	if good < 3 {
+		t.Errorf("the good watcher should keep running, ran %d times", good)
+	}
+	// 20ms, 40ms, 80ms, 160ms backoff leaves time for about 4 runs
+	if bad < 2 || bad > 5 {
+		t.Errorf("the failing watcher should back off, ran %d times", bad)
+	}
+	if errs != bad {
+		t.Errorf("every failure should be reported: %d errors, %d failures", errs, bad)
+	}
+}
+
+func TestSchedulerCatchUp(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		now = time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+		s   = NewScheduler(time.Hour)
+		ran = make(chan struct{}, 10)
+	)
+	s.Jitter = 0
+	s.Tick = 5 * time.Millisecond
+	s.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go s.Run(ctx, WatcherFunc(func() error {
+		ran <- struct{}{}
+		return nil
+	}))
+	<-ran
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-ran:
+		t.Fatal("should wait for the interval")
+	default:
+	}
+	// the computer is suspended for two hours while waiting
+	mu.Lock()
+	now = now.Add(2 * time.Hour)
+	mu.Unlock()
+	select {
+	case <-ran:
+	case <-ctx.Done():
+		t.Fatal("the missed check was not caught up")
+	}
+}
+
+func TestSchedulerTrigger(t *testing.T) {
+	s := NewScheduler(time.Hour)
+	s.Jitter = 0
+	s.Tick = 5 * time.Millisecond
+	ran := make(chan struct{}, 10)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go s.Run(ctx, WatcherFunc(func() error {
+		ran <- struct{}{}
+		return nil
+	}))
+	<-ran
+	time.Sleep(10 * time.Millisecond)
+	if next := s.Next(); next.Before(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("the next run should be in an hour, got %v", next)
+	}
+	s.Trigger()
+	select {
+	case <-ran:
+	case <-ctx.Done():
+		t.Fatal("trigger should run the watcher right away")
+	}
+}
+
+func TestSchedulerTriggerWhileRunning(t *testing.T) {
+	s := NewScheduler(time.Hour)
+	s.Tick = 5 * time.Millisecond
+	var (
+		started     = make(chan struct{}, 10)
+		release     = make(chan struct{})
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	)
+	defer cancel()
+	go s.Run(ctx, WatcherFunc(func() error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}))
+	<-started
+	s.Trigger()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal("a trigger while the watcher is running should run it again")
+	}
+}
+
+func TestSchedulerShutdown(t *testing.T) {
+	var (
+		s           = NewScheduler(time.Hour)
+		started     = make(chan struct{})
+		finished    bool
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+	go func() {
+		<-started
+		cancel()
+	}()
+	err := s.Run(ctx, WatcherFunc(func() error {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		finished = true
+		return nil
+	}))
+	if err != context.Canceled {
+		t.Errorf("expected the context to be canceled, got %v", err)
+	}
+	if !finished {
+		t.Error("run should wait for running watchers to finish")
+	}
+}
+
+func TestPollFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "edu-poll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	changed := make(chan struct{}, 10)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go PollFile(ctx, f.Name(), 5*time.Millisecond, func() { changed <- struct{}{} })
+
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-changed:
+		t.Fatal("the file has not changed")
+	default:
+	}
+	if err = os.Chtimes(f.Name(), time.Now(), time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-ctx.Done():
+		t.Fatal("the change was not seen")
+	}
+}
+
+func TestAlerts(t *testing.T) {
+	quiet, err := ParseQuietHours("22:00-07:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quiet.String() != "22:00-07:30" {
+		t.Errorf("wrong quiet hours: %v", quiet)
+	}
+	day := func(h, m int) time.Time {
+		return time.Date(2021, time.March, 1, h, m, 0, 0, time.Local)
+	}
+	for _, tc := range []struct {
+		t    time.Time
+		want bool
+	}{
+		{day(21, 59), false}, {day(22, 0), true}, {day(3, 0), true},
+		{day(7, 29), true}, {day(7, 30), false}, {day(12, 0), false},
+	} {
+		if quiet.Contains(tc.t) != tc.want {
+			t.Errorf("%v: got %v", tc.t.Format("15:04"), !tc.want)
+		}
+	}
+	var sent []string
+	alerts := NewAlerts(quiet, func(msg string) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	alerts.Send("a", day(12, 0))
+	alerts.Send("b", day(23, 0))
+	alerts.Send("c", day(3, 0))
+	alerts.Flush(day(5, 0))
+	if len(sent) != 1 || alerts.Queued() != 2 {
+		t.Fatalf("alerts should be queued during quiet hours: %q", sent)
+	}
+	alerts.Flush(day(8, 0))
+	if len(sent) != 2 || sent[1] != "b\nc" || alerts.Queued() != 0 {
+		t.Errorf("queued alerts should be sent together: %q", sent)
+	}
+
+	dir, err := ioutil.TempDir("", "edu-alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "state.json")
+	state, err := OpenState(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent = nil
+	if err = alerts.Persist(state); err != nil {
+		t.Fatal(err)
+	}
+	alerts.Send("d", day(23, 0))
+	// restart during quiet hours
+	if state, err = OpenState(file); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Queue) != 1 || state.Queue[0] != "d" {
+		t.Fatalf("queued alerts should be saved: %q", state.Queue)
+	}
+	alerts = NewAlerts(quiet, func(msg string) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	if err = alerts.Persist(state); err != nil {
+		t.Fatal(err)
+	}
+	alerts.Send("e", day(1, 0))
+	alerts.Flush(day(8, 0))
+	if len(sent) != 1 || sent[0] != "d\ne" {
+		t.Errorf("saved alerts should be sent after a restart: %q", sent)
+	}
+	if state, err = OpenState(file); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Queue) != 0 {
+		t.Errorf("sent alerts should be removed from the state: %q", state.Queue)
+	}
+
+	for _, s := range []string{"", "22:00", "22-07", "10pm-7am"} {
+		if _, err := ParseQuietHours(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
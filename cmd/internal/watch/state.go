@@ -0,0 +1,124 @@
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Status is the last known seats of a course.
+type Status struct {
+	Seats      int       `json:"seats"`
+	Waitlisted int       `json:"waitlisted"`
+	Time       time.Time `json:"time"`
+}
+
+// Open returns true if the course has seats open.
+func (s Status) Open() bool {
+	return s.Seats > 0
+}
+
+// Change is a change in a course's seats that
+// should be sent as a notification.
+type Change struct {
+	CRN int
+	// Old is the status before the change, it is only
+	// valid if Seen is true.
+	Old  Status
+	New  Status
+	Seen bool
+}
+
+func (c *Change) String() string {
+	s := fmt.Sprintf("%d seats", c.New.Seats)
+	if c.Seen {
+		s = fmt.Sprintf("%d → %d seats", c.Old.Seats, c.New.Seats)
+	}
+	if c.New.Waitlisted > 0 {
+		s += fmt.Sprintf(" (%d waitlisted)", c.New.Waitlisted)
+	}
+	return s
+}
+
+// State is the last known status of each course being
+// watched. It is saved to a file so that it is not lost
+// when the watch is restarted.
+type State struct {
+	file string
+	mu   sync.Mutex
+	// Threshold is a number of seats that will cause a
+	// change when the seats go above or below it.
+	Threshold int            `json:"threshold"`
+	CRNs      map[int]Status `json:"crns"`
+	// Queue is the alerts waiting for quiet hours to end.
+	Queue []string `json:"queue,omitempty"`
+}
+
+// OpenState will read the state from a file. An
+// empty state is returned if the file does not exist.
+func OpenState(file string) (*State, error) {
+	s := &State{file: file, CRNs: make(map[int]Status)}
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("could not read watch state %s: %w", file, err)
+	}
+	if s.CRNs == nil {
+		s.CRNs = make(map[int]Status)
+	}
+	return s, nil
+}
+
+// Update sets the status of a course and returns the change
+// if it should be sent out. A change is returned when a course
+// opens, closes, or crosses the threshold. A course that has
+// not been seen before is only a change if it is open.
+func (s *State) Update(crn int, st Status) (*Change, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, seen := s.CRNs[crn]
+	s.CRNs[crn] = st
+	ch := &Change{CRN: crn, Old: old, New: st, Seen: seen}
+	if !seen {
+		return ch, st.Open()
+	}
+	if old.Open() != st.Open() {
+		return ch, true
+	}
+	if s.Threshold > 0 && (old.Seats >= s.Threshold) != (st.Seats >= s.Threshold) {
+		return ch, true
+	}
+	return ch, false
+}
+
+// Save writes the state to its file.
+func (s *State) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(s.file), 0775); err != nil {
+		return err
+	}
+	tmp := s.file + ".tmp"
+	if err = ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.file)
+}
+
+func (s *State) setQueue(queue []string) {
+	s.mu.Lock()
+	s.Queue = append([]string(nil), queue...)
+	s.mu.Unlock()
+}
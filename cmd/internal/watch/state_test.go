@@ -0,0 +1,62 @@
+package watch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "edu-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "state", "2021-fall.json")
+	state, err := OpenState(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Threshold = 5
+
+	for i, tc := range []struct {
+		seats  int
+		change bool
+		msg    string
+	}{
+		{0, false, ""},
+		{0, false, ""},
+		{3, true, "0 → 3 seats"},
+		{4, false, ""},
+		{6, true, "4 → 6 seats"},
+		{8, false, ""},
+		{2, true, "8 → 2 seats"},
+		{0, true, "2 → 0 seats"},
+	} {
+		ch, ok := state.Update(30151, Status{Seats: tc.seats})
+		if ok != tc.change {
+			t.Errorf("%d: got change %v; want %v", i, ok, tc.change)
+		}
+		if ok && ch.String() != tc.msg {
+			t.Errorf("%d: got %q; want %q", i, ch.String(), tc.msg)
+		}
+	}
+	if ch, ok := state.Update(30152, Status{Seats: 2, Waitlisted: 1}); !ok || ch.String() != "2 seats (1 waitlisted)" {
+		t.Errorf("a new open course should be a change: %v %v", ok, ch)
+	}
+	if err = state.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = OpenState(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.CRNs) != 2 || state.CRNs[30152].Seats != 2 {
+		t.Fatalf("state was not saved: %+v", state.CRNs)
+	}
+	if _, ok := state.Update(30152, Status{Seats: 2}); ok {
+		t.Error("state should survive a restart")
+	}
+}
@@ -41,3 +41,21 @@ func TestColorf(t *testing.T) {
 		}
 	}
 }
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		vals []int
+		exp  string
+	}{
+		{nil, ""},
+		{[]int{3, 3, 3}, "▁▁▁"},
+		{[]int{0, 7}, "▁█"},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{[]int{10, 0, 5}, "█▁▄"},
+	}
+	for _, tst := range tests {
+		if res := Sparkline(tst.vals); res != tst.exp {
+			t.Errorf("wrong sparkline for %v: got %s; want %s", tst.vals, res, tst.exp)
+		}
+	}
+}
@@ -84,3 +84,30 @@ func control(code string) string {
 	}
 	return fmt.Sprintf("%s[%s", escape, code)
 }
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline returns a one line bar graph of the values.
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		if max == min {
+			line[i] = sparks[0]
+			continue
+		}
+		line[i] = sparks[(v-min)*(len(sparks)-1)/(max-min)]
+	}
+	return string(line)
+}
@@ -0,0 +1,123 @@
+// Package banner adds school providers for schools that run the
+// Ellucian Banner 8 schedule pages. UC Merced is one of these
+// schools, so the scraping is done by the ucm package.
+package banner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+	"github.com/harrybrwn/edu/school/ucmerced/ucm"
+)
+
+// Config is the configuration for a school that runs Banner 8.
+type Config struct {
+	// Name is used to pick the school with --school.
+	Name    string   `yaml:"name"`
+	Aliases []string `yaml:"aliases"`
+	Title   string   `yaml:"title"`
+	// Scheme defaults to https.
+	Scheme string `yaml:"scheme"`
+	Host   string `yaml:"host"`
+	// Path is the path that the schedule pages are
+	// under, i.e. /pls/PROD
+	Path string `yaml:"path"`
+	// Terms maps term names to the suffix of the term code,
+	// the full term code is the year followed by the suffix.
+	Terms map[string]string `yaml:"terms"`
+	// Columns maps column names to their index in the
+	// schedule table. Any that are left out will use
+	// the UC Merced layout.
+	Columns map[string]int `yaml:"columns"`
+	// TimeZone is the name of the school's time
+	// zone, i.e. America/New_York.
+	TimeZone string `yaml:"timezone"`
+}
+
+// Site creates a ucm site from the config.
+func (c *Config) Site() (*ucm.Site, error) {
+	if c.Host == "" {
+		return nil, fmt.Errorf("banner school %q has no host", c.Name)
+	}
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	cols, err := columns(c.Columns)
+	if err != nil {
+		return nil, fmt.Errorf("banner school %q: %w", c.Name, err)
+	}
+	if c.TimeZone != "" {
+		if _, err = time.LoadLocation(c.TimeZone); err != nil {
+			return nil, fmt.Errorf("banner school %q: %w", c.Name, err)
+		}
+	}
+	site, err := ucm.NewSite(scheme+"://"+c.Host+c.Path, c.Terms, cols)
+	if err != nil {
+		return nil, err
+	}
+	site.TimeZone = c.TimeZone
+	return site, nil
+}
+
+// Provider creates a school provider from the config.
+func (c *Config) Provider() (*school.Provider, error) {
+	if c.Name == "" {
+		return nil, fmt.Errorf("banner school with host %q has no name", c.Host)
+	}
+	site, err := c.Site()
+	if err != nil {
+		return nil, err
+	}
+	title := c.Title
+	if title == "" {
+		title = c.Name
+	}
+	return site.Provider(c.Name, title, c.Aliases...), nil
+}
+
+// Register will register the school as a provider. An error is
+// returned if the config is not valid or the name is taken.
+func Register(c Config) (*school.Provider, error) {
+	for _, name := range append([]string{c.Name}, c.Aliases...) {
+		if _, err := school.Lookup(name); err == nil {
+			return nil, fmt.Errorf("school %q is already registered", name)
+		}
+	}
+	p, err := c.Provider()
+	if err != nil {
+		return nil, err
+	}
+	school.Register(p)
+	return p, nil
+}
+
+func columns(m map[string]int) (ucm.Columns, error) {
+	cols := ucm.DefaultColumns
+	fields := map[string]*int{
+		"crn":        &cols.CRN,
+		"code":       &cols.Code,
+		"title":      &cols.Title,
+		"units":      &cols.Units,
+		"activity":   &cols.Activity,
+		"days":       &cols.Days,
+		"time":       &cols.Time,
+		"room":       &cols.Room,
+		"dates":      &cols.Dates,
+		"instructor": &cols.Instructor,
+		"capacity":   &cols.Capacity,
+		"enrolled":   &cols.Enrolled,
+		"seats":      &cols.Seats,
+		"count":      &cols.Count,
+	}
+	for name, i := range m {
+		f, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return cols, fmt.Errorf("unknown column %q", name)
+		}
+		*f = i
+	}
+	return cols, cols.Validate()
+}
@@ -0,0 +1,162 @@
+package banner
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+	"github.com/harrybrwn/edu/school/ucmerced/ucm"
+)
+
+func TestRegister(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/prod/xhwschedule.P_ViewSchedule", func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadFile(filepath.Join("testdata", "schedule-"+r.URL.Query().Get("validterm")+".html"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(b)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Register(Config{
+		Name:    "testbanner",
+		Aliases: []string{"tb"},
+		Scheme:  "http",
+		Host:    u.Host,
+		Path:    "/prod",
+		Terms:   map[string]string{"fall": "09"},
+		Columns: map[string]int{"code": 0, "crn": 1, "count": 14},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found, err := school.Lookup("tb"); err != nil || found != p {
+		t.Fatal("provider should be registered by its alias")
+	}
+	if p.Title != "testbanner" {
+		t.Errorf("title should default to the name, got %q", p.Title)
+	}
+	if _, err = Register(Config{Name: "testbanner", Host: "example.com"}); err == nil {
+		t.Error("expected an error when registering the same name twice")
+	}
+
+	sched, err := p.New(context.Background(), &school.Config{Year: 2021, Term: "fall"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sched.Len() != 2 {
+		t.Fatalf("expected 2 courses, got %d", sched.Len())
+	}
+	c := sched.Get(90101)
+	if c == nil {
+		t.Fatal("could not find 90101")
+	}
+	if subj, num := c.Code(); subj != "HIST" || num != "101" || c.SeatsOpen() != 2 {
+		t.Errorf("wrong course: %s %s with %d seats", subj, num, c.SeatsOpen())
+	}
+	if crs := c.(*ucm.Course); crs.Exam == nil || crs.Instructor != "Jones, Ann" {
+		t.Errorf("wrong course details: %+v", crs)
+	}
+	if _, err = p.New(context.Background(), &school.Config{Year: 2021, Term: "spring"}); err == nil {
+		t.Error("expected an error for an unknown term")
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	terms := map[string]string{"fall": "09"}
+	for _, c := range []Config{
+		{Host: "example.com", Terms: terms},
+		{Name: "nohost", Terms: terms},
+		{Name: "noterms", Host: "example.com"},
+		{Name: "badcol", Host: "example.com", Terms: terms, Columns: map[string]int{"cmp": 1}},
+		{Name: "outside", Host: "example.com", Terms: terms, Columns: map[string]int{"seats": 13}},
+		{Name: "badzone", Host: "example.com", Terms: terms, TimeZone: "Pacific/Nowhere"},
+	} {
+		if _, err := c.Provider(); err == nil {
+			t.Errorf("expected an error for %+v", c)
+		}
+	}
+}
+
+func TestQuarterSchool(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/prod/xhwschedule.p_selectsubject", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join("testdata", "selectsubject.html"))
+	})
+	mux.HandleFunc("/prod/xhwschedule.P_ViewSchedule", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join("testdata", "schedule-"+r.URL.Query().Get("validterm")+".html"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := (&Config{
+		Name:     "quarters",
+		Scheme:   "http",
+		Host:     u.Host,
+		Path:     "/prod",
+		TimeZone: "America/Chicago",
+		// "20" is summer at UC Merced
+		Terms: map[string]string{"fall": "09", "winter": "20", "spring": "30"},
+		Columns: map[string]int{
+			"instructor": 5, "days": 6, "time": 7,
+			"room": 8, "dates": 9, "capacity": 10,
+			"enrolled": 11, "seats": 12, "count": 13,
+		},
+	}).Provider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if site := ucm.ProviderSite(p); site == nil || site.TimeZone != "America/Chicago" {
+		t.Error("the provider should have a banner site in its time zone")
+	}
+	if p.TimeZone != "America/Chicago" {
+		t.Errorf("wrong time zone: %q", p.TimeZone)
+	}
+	ctx := context.Background()
+	if err = p.Check(ctx, &school.Config{Year: 2021, Term: "winter", CourseName: "bio"}); err != nil {
+		t.Errorf("winter should be offered: %v", err)
+	}
+	if err = p.Check(ctx, &school.Config{Year: 2021, Term: "spring"}); err == nil {
+		t.Error("spring is not on the subject page")
+	}
+
+	sched, err := p.New(ctx, &school.Config{Year: 2021, Term: "winter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := sched.Get(70001).(*ucm.Course)
+	if !ok {
+		t.Fatal("could not find 70001")
+	}
+	if c.Instructor != "Park, Lee" || c.SeatsOpen() != 10 {
+		t.Errorf("wrong course: %+v", c)
+	}
+	if len(c.Meetings) != 2 {
+		t.Fatalf("expected 2 meetings, got %d", len(c.Meetings))
+	}
+	lab := c.Meetings[1]
+	if lab.Activity != "LAB" || lab.Instructor != "Kim, Dana" || lab.BuildingRoom != "SCI 101" ||
+		!reflect.DeepEqual(lab.Days, []time.Weekday{time.Friday}) || lab.Time.Start.Hour() != 14 {
+		t.Errorf("the meeting was not parsed with the site's columns: %+v", lab)
+	}
+	if c.Exam == nil || c.Exam.Day != time.Tuesday || c.Exam.Building != "SCI 100" || c.Exam.Date.Day() != 23 {
+		t.Errorf("wrong exam: %+v", c.Exam)
+	}
+}
@@ -0,0 +1,205 @@
+// Package banner9 gets class schedules from schools that run
+// Ellucian Banner 9 Student Registration Self-Service, which has
+// a json api for its class search.
+package banner9
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harrybrwn/errs"
+)
+
+// DefaultPageSize is the number of sections
+// requested for each page of search results.
+const DefaultPageSize = 500
+
+// Client is a client for the class search api of one school.
+// The class search needs a session cookie with the term selected
+// so each client keeps its own cookie jar.
+type Client struct {
+	base   url.URL
+	client *http.Client
+	// mu is held during a search because the
+	// search depends on the session's term
+	mu sync.Mutex
+	// PageSize is the number of sections
+	// requested for each page.
+	PageSize int
+}
+
+// New creates a client given the url of the Student Registration
+// Self-Service, i.e. https://reg.school.edu/StudentRegistrationSsb
+func New(base string) (*Client, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base url %q must have a scheme and host", base)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		base:     *u,
+		client:   &http.Client{Jar: jar, Timeout: time.Minute},
+		PageSize: DefaultPageSize,
+	}, nil
+}
+
+// SetTransport sets the transport used by the client.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.client.Transport = rt
+}
+
+// BaseURL returns the url that requests are sent to.
+func (c *Client) BaseURL() string {
+	return c.base.String()
+}
+
+// Term is a term that has a class schedule.
+type Term struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// Terms gets the terms that can be searched.
+func (c *Client) Terms(ctx context.Context) ([]Term, error) {
+	q := url.Values{
+		"searchTerm": {""},
+		"offset":     {"1"},
+		"max":        {"100"},
+	}
+	terms := make([]Term, 0)
+	return terms, c.getJSON(ctx, "/ssb/classSearch/getTerms", q, &terms)
+}
+
+// SearchOptions are the options for a class search.
+type SearchOptions struct {
+	// Term is the term code, i.e. 202110
+	Term string
+	// Subject is a subject code, empty for every subject.
+	Subject string
+	// Open will only get sections with seats open.
+	Open bool
+}
+
+// Search will get all the sections that match the search options.
+func (c *Client) Search(ctx context.Context, opts SearchOptions) ([]*Section, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.selectTerm(ctx, opts.Term); err != nil {
+		return nil, err
+	}
+	size := c.PageSize
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	sections := make([]*Section, 0, size)
+	for {
+		q := url.Values{
+			"txt_term":      {opts.Term},
+			"pageOffset":    {strconv.Itoa(len(sections))},
+			"pageMaxSize":   {strconv.Itoa(size)},
+			"sortColumn":    {"subjectDescription"},
+			"sortDirection": {"asc"},
+		}
+		if opts.Subject != "" {
+			q.Set("txt_subject", strings.ToUpper(opts.Subject))
+		}
+		if opts.Open {
+			q.Set("chk_open_only", "true")
+		}
+		var page searchResults
+		if err := c.getJSON(ctx, "/ssb/searchResults/searchResults", q, &page); err != nil {
+			return nil, err
+		}
+		if !page.Success {
+			return nil, fmt.Errorf("class search for term %s was not successful", opts.Term)
+		}
+		sections = append(sections, page.Data...)
+		if len(page.Data) == 0 || len(sections) >= page.TotalCount {
+			break
+		}
+	}
+	return sections, nil
+}
+
+type searchResults struct {
+	Success    bool       `json:"success"`
+	TotalCount int        `json:"totalCount"`
+	PageOffset int        `json:"pageOffset"`
+	Data       []*Section `json:"data"`
+}
+
+// selectTerm does the handshake that the class search needs before
+// searching. The term is saved in the session and any old search
+// is cleared out.
+func (c *Client) selectTerm(ctx context.Context, term string) error {
+	if term == "" {
+		return errs.New("no term given")
+	}
+	resp, err := c.client.Do(c.newRequest(ctx, "POST", "/ssb/term/search", url.Values{"mode": {"search"}}, url.Values{"term": {term}}))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not select term %s: %s", term, resp.Status)
+	}
+	resp, err = c.client.Do(c.newRequest(ctx, "POST", "/ssb/classSearch/resetDataForm", nil, nil))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not reset the search: %s", resp.Status)
+	}
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, p string, query, form url.Values) *http.Request {
+	u := c.base
+	u.Path = path.Join(c.base.Path, p)
+	u.RawQuery = query.Encode()
+	req := &http.Request{
+		Method: method,
+		Proto:  "HTTP/1.1",
+		URL:    &u,
+		Header: make(http.Header),
+	}
+	req = req.WithContext(ctx)
+	if form != nil {
+		body := form.Encode()
+		req.Body = ioutil.NopCloser(strings.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return req
+}
+
+func (c *Client) getJSON(ctx context.Context, p string, query url.Values, v interface{}) error {
+	req := c.newRequest(ctx, "GET", p, query, nil)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", p, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,270 @@
+package banner9
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+const basePath = "/StudentRegistrationSsb"
+
+// testServer is a stand-in for the class search api. It keeps
+// the term that each session selected and only answers searches
+// for that term, the same way that banner does.
+type testServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	sessions map[string]string
+	searches int
+}
+
+func newTestServer() *testServer {
+	ts := &testServer{sessions: make(map[string]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc(basePath+"/ssb/term/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Query().Get("mode") != "search" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		ts.mu.Lock()
+		id := strconv.Itoa(len(ts.sessions) + 1)
+		ts.sessions[id] = r.PostFormValue("term")
+		ts.mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: id, Path: basePath})
+		fmt.Fprint(w, `{"fwdURL":"/StudentRegistrationSsb/ssb/classSearch/classSearch"}`)
+	})
+	mux.HandleFunc(basePath+"/ssb/classSearch/resetDataForm", func(w http.ResponseWriter, r *http.Request) {
+		if ts.term(r) == "" {
+			http.Error(w, "no session", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "true")
+	})
+	mux.HandleFunc(basePath+"/ssb/classSearch/getTerms", func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(w, r, "terms.json")
+	})
+	mux.HandleFunc(basePath+"/ssb/searchResults/searchResults", ts.search)
+	ts.Server = httptest.NewServer(mux)
+	return ts
+}
+
+func (ts *testServer) term(r *http.Request) string {
+	c, err := r.Cookie("JSESSIONID")
+	if err != nil {
+		return ""
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.sessions[c.Value]
+}
+
+func (ts *testServer) search(w http.ResponseWriter, r *http.Request) {
+	ts.mu.Lock()
+	ts.searches++
+	ts.mu.Unlock()
+	q := r.URL.Query()
+	term := ts.term(r)
+	if term == "" || term != q.Get("txt_term") {
+		// banner gives back an unsuccessful search
+		// if the term was not selected first
+		fmt.Fprint(w, `{"success":false,"totalCount":0,"data":null}`)
+		return
+	}
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "search-"+term+".json"))
+	if err != nil {
+		fmt.Fprint(w, `{"success":true,"totalCount":0,"data":[]}`)
+		return
+	}
+	var all, found []json.RawMessage
+	if err = json.Unmarshal(b, &all); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, raw := range all {
+		var s Section
+		json.Unmarshal(raw, &s)
+		if subj := q.Get("txt_subject"); subj != "" && subj != s.Subject {
+			continue
+		}
+		if q.Get("chk_open_only") == "true" && !s.OpenSection {
+			continue
+		}
+		found = append(found, raw)
+	}
+	offset, _ := strconv.Atoi(q.Get("pageOffset"))
+	size, _ := strconv.Atoi(q.Get("pageMaxSize"))
+	page := found[min(offset, len(found)):min(offset+size, len(found))]
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":    true,
+		"totalCount": len(found),
+		"pageOffset": offset,
+		"data":       page,
+	})
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func serveFixture(w http.ResponseWriter, r *http.Request, name string) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+func testClient(t *testing.T, srv *testServer) *Client {
+	t.Helper()
+	c, err := New(srv.URL + basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestSearch(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	c := testClient(t, srv)
+	c.PageSize = 2
+
+	sections, err := c.Search(context.Background(), SearchOptions{Term: "202110"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d", len(sections))
+	}
+	if srv.searches != 2 {
+		t.Errorf("expected 2 pages, got %d", srv.searches)
+	}
+
+	sections, err = c.Search(context.Background(), SearchOptions{Term: "202110", Subject: "cse", Open: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 1 || sections[0].ID() != 30151 {
+		t.Errorf("expected only the open cse section, got %d sections", len(sections))
+	}
+	if _, err = c.Search(context.Background(), SearchOptions{}); err == nil {
+		t.Error("expected an error with no term")
+	}
+}
+
+func TestSearchWithoutTerm(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	c := testClient(t, srv)
+	var page searchResults
+	err := c.getJSON(context.Background(), "/ssb/searchResults/searchResults", map[string][]string{"txt_term": {"202110"}}, &page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Success {
+		t.Error("search should fail before the term is selected")
+	}
+}
+
+func TestSection(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	sections, err := testClient(t, srv).Search(context.Background(), SearchOptions{Term: "202110"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sched := NewSchedule(sections)
+	var lect school.Course = sched.Get(30151)
+	if lect == nil {
+		t.Fatal("could not find 30151")
+	}
+	if lect.Name() != "Algorithm Design & Analysis" {
+		t.Errorf("title should be unescaped, got %q", lect.Name())
+	}
+	if subj, num := lect.Code(); subj != "CSE" || num != "100" || lect.SectionType() != "Lecture" {
+		t.Errorf("wrong course: %s %s %s", subj, num, lect.SectionType())
+	}
+	if names := lect.Instructors(); len(names) != 2 || names[0] != "Doe, John" {
+		t.Errorf("primary instructor should be first: %v", names)
+	}
+	if lect.Credits() != 4 || lect.SeatsOpen() != 10 {
+		t.Errorf("wrong credits or seats: %v %d", lect.Credits(), lect.SeatsOpen())
+	}
+	meetings := lect.MeetingTimes()
+	if len(meetings) != 2 {
+		t.Fatalf("expected 2 meetings, got %d", len(meetings))
+	}
+	m := meetings[0]
+	if len(m.Days) != 2 || m.Days[0] != time.Tuesday || m.Days[1] != time.Thursday {
+		t.Errorf("wrong days: %v", m.Days)
+	}
+	if m.Start.Hour() != 13 || m.Start.Minute() != 30 || m.End.Hour() != 14 {
+		t.Errorf("wrong time: %v-%v", m.Start, m.End)
+	}
+	if m.Location != "COB 110" || m.Instructor != "Doe, John" || m.StartDate.Month() != time.January {
+		t.Errorf("wrong meeting: %+v", m)
+	}
+	if meetings[1].Instructor != "Lee, Amy" {
+		t.Errorf("meeting faculty should be used, got %q", meetings[1].Instructor)
+	}
+
+	lab := sched.Get(30152)
+	if e := lab.Enrollment(); e.Waitlisted != 4 || e.Capacity != 25 || lab.SeatsOpen() != 0 {
+		t.Errorf("wrong enrollment: %+v", e)
+	}
+	if tbd := lab.MeetingTimes()[0]; !tbd.TBD() || len(tbd.Days) != 0 || tbd.Location != "" {
+		t.Errorf("lab should be TBD: %+v", tbd)
+	}
+	if sched.Get(1) != nil || sched.Len() != 3 {
+		t.Error("wrong schedule")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p, err := Register(Config{
+		Name:  "testbanner9",
+		URL:   srv.URL + basePath,
+		Terms: map[string]string{"spring": "10", "fall": "30"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Register(Config{Name: "testbanner9", URL: srv.URL}); err == nil {
+		t.Error("expected an error for a name that is taken")
+	}
+	conf := &school.Config{Year: 2021, Term: "spring", CourseName: "MATH"}
+	if err = p.Check(context.Background(), conf); err != nil {
+		t.Error(err)
+	}
+	if err = p.Check(context.Background(), &school.Config{Year: 2020, Term: "fall"}); err == nil || !strings.Contains(err.Error(), "not offered") {
+		t.Errorf("expected a not offered error, got %v", err)
+	}
+	sched, err := p.New(context.Background(), conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sched.Len() != 1 || sched.Get(30200) == nil {
+		t.Errorf("expected only the math section, got %d", sched.Len())
+	}
+	if _, err = p.New(context.Background(), &school.Config{Year: 2021, Term: "winter"}); err == nil {
+		t.Error("expected an error for an unknown term")
+	}
+}
@@ -0,0 +1,116 @@
+package banner9
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+// Config is the configuration for a school that runs Banner 9.
+type Config struct {
+	// Name is used to pick the school with --school.
+	Name    string   `yaml:"name"`
+	Aliases []string `yaml:"aliases"`
+	Title   string   `yaml:"title"`
+	// URL is the url of the Student Registration
+	// Self-Service, i.e. https://reg.school.edu/StudentRegistrationSsb
+	URL string `yaml:"url"`
+	// Terms maps term names to the suffix of the term code,
+	// the full term code is the year followed by the suffix.
+	Terms map[string]string `yaml:"terms"`
+	// TimeZone is the name of the school's time
+	// zone, i.e. America/New_York.
+	TimeZone string `yaml:"timezone"`
+}
+
+// TermCode returns the term code for a year and term name.
+func (c *Config) TermCode(year int, term string) (string, error) {
+	suffix, ok := c.Terms[strings.ToLower(term)]
+	if !ok {
+		return "", fmt.Errorf("could not find term %s", term)
+	}
+	return fmt.Sprintf("%d%s", year, suffix), nil
+}
+
+// Provider creates a school provider from the config.
+func (c *Config) Provider() (*school.Provider, error) {
+	if c.Name == "" {
+		return nil, fmt.Errorf("banner 9 school with url %q has no name", c.URL)
+	}
+	if len(c.Terms) == 0 {
+		return nil, fmt.Errorf("banner 9 school %q has no terms", c.Name)
+	}
+	client, err := New(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("banner 9 school %q: %w", c.Name, err)
+	}
+	return c.provider(client), nil
+}
+
+func (c *Config) provider(client *Client) *school.Provider {
+	title := c.Title
+	if title == "" {
+		title = c.Name
+	}
+	return &school.Provider{
+		Name:         c.Name,
+		Aliases:      c.Aliases,
+		Title:        title,
+		BaseURL:      client.BaseURL(),
+		Terms:        c.Terms,
+		Capabilities: school.Seats | school.Meetings | school.Offerings,
+		TimeZone:     c.TimeZone,
+		New: func(ctx context.Context, conf *school.Config) (school.Schedule, error) {
+			code, err := c.TermCode(conf.Year, conf.Term)
+			if err != nil {
+				return nil, err
+			}
+			sections, err := client.Search(ctx, SearchOptions{
+				Term:    code,
+				Subject: conf.CourseName,
+				Open:    conf.FilterClosed,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return NewSchedule(sections), nil
+		},
+		Check: func(ctx context.Context, conf *school.Config) error {
+			code, err := c.TermCode(conf.Year, conf.Term)
+			if err != nil {
+				return err
+			}
+			terms, err := client.Terms(ctx)
+			if err != nil {
+				// the search may still work so don't fail
+				log.Printf("could not get banner 9 terms: %v\n", err)
+				return nil
+			}
+			for _, t := range terms {
+				if t.Code == code {
+					return nil
+				}
+			}
+			return fmt.Errorf("term %q is not offered for %d", conf.Term, conf.Year)
+		},
+	}
+}
+
+// Register will register the school as a provider. An error is
+// returned if the config is not valid or the name is taken.
+func Register(c Config) (*school.Provider, error) {
+	for _, name := range append([]string{c.Name}, c.Aliases...) {
+		if _, err := school.Lookup(name); err == nil {
+			return nil, fmt.Errorf("school %q is already registered", name)
+		}
+	}
+	p, err := c.Provider()
+	if err != nil {
+		return nil, err
+	}
+	school.Register(p)
+	return p, nil
+}
@@ -0,0 +1,241 @@
+package banner9
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+// Section is a section from the class search results.
+type Section struct {
+	SectionID               int              `json:"id"`
+	Term                    string           `json:"term"`
+	TermDesc                string           `json:"termDesc"`
+	CourseReferenceNumber   string           `json:"courseReferenceNumber"`
+	PartOfTerm              string           `json:"partOfTerm"`
+	CourseNumber            string           `json:"courseNumber"`
+	Subject                 string           `json:"subject"`
+	SubjectDescription      string           `json:"subjectDescription"`
+	SequenceNumber          string           `json:"sequenceNumber"`
+	CampusDescription       string           `json:"campusDescription"`
+	ScheduleTypeDescription string           `json:"scheduleTypeDescription"`
+	CourseTitle             string           `json:"courseTitle"`
+	CreditHours             *float64         `json:"creditHours"`
+	CreditHourLow           *float64         `json:"creditHourLow"`
+	CreditHourHigh          *float64         `json:"creditHourHigh"`
+	MaximumEnrollment       int              `json:"maximumEnrollment"`
+	Enrolled                int              `json:"enrollment"`
+	SeatsAvailable          int              `json:"seatsAvailable"`
+	WaitCapacity            int              `json:"waitCapacity"`
+	WaitCount               int              `json:"waitCount"`
+	WaitAvailable           int              `json:"waitAvailable"`
+	OpenSection             bool             `json:"openSection"`
+	Faculty                 []Faculty        `json:"faculty"`
+	MeetingsFaculty         []MeetingFaculty `json:"meetingsFaculty"`
+}
+
+// Faculty is an instructor of a section.
+type Faculty struct {
+	BannerID         string `json:"bannerId"`
+	DisplayName      string `json:"displayName"`
+	EmailAddress     string `json:"emailAddress"`
+	PrimaryIndicator bool   `json:"primaryIndicator"`
+}
+
+// MeetingFaculty is a meeting time and the
+// faculty that teach it.
+type MeetingFaculty struct {
+	Category    string      `json:"category"`
+	Faculty     []Faculty   `json:"faculty"`
+	MeetingTime MeetingTime `json:"meetingTime"`
+}
+
+// MeetingTime is when and where a section meets.
+type MeetingTime struct {
+	BeginTime              string   `json:"beginTime"`
+	EndTime                string   `json:"endTime"`
+	Building               string   `json:"building"`
+	BuildingDescription    string   `json:"buildingDescription"`
+	Room                   string   `json:"room"`
+	StartDate              string   `json:"startDate"`
+	EndDate                string   `json:"endDate"`
+	MeetingScheduleType    string   `json:"meetingScheduleType"`
+	MeetingTypeDescription string   `json:"meetingTypeDescription"`
+	HoursWeek              *float64 `json:"hoursWeek"`
+
+	Sunday    bool `json:"sunday"`
+	Monday    bool `json:"monday"`
+	Tuesday   bool `json:"tuesday"`
+	Wednesday bool `json:"wednesday"`
+	Thursday  bool `json:"thursday"`
+	Friday    bool `json:"friday"`
+	Saturday  bool `json:"saturday"`
+}
+
+// Days returns the days of the week that the meeting is on.
+func (mt *MeetingTime) Days() []time.Weekday {
+	days := make([]time.Weekday, 0, 7)
+	for i, on := range []bool{
+		mt.Sunday, mt.Monday, mt.Tuesday, mt.Wednesday,
+		mt.Thursday, mt.Friday, mt.Saturday,
+	} {
+		if on {
+			days = append(days, time.Weekday(i))
+		}
+	}
+	return days
+}
+
+// Location returns the building and room.
+func (mt *MeetingTime) Location() string {
+	return strings.TrimSpace(mt.Building + " " + mt.Room)
+}
+
+// ID returns the section's CRN.
+func (s *Section) ID() int {
+	crn, err := strconv.Atoi(strings.TrimSpace(s.CourseReferenceNumber))
+	if err != nil {
+		return 0
+	}
+	return crn
+}
+
+// Name returns the title of the course.
+func (s *Section) Name() string {
+	return html.UnescapeString(s.CourseTitle)
+}
+
+// SeatsOpen returns the number of seats available.
+func (s *Section) SeatsOpen() int {
+	return s.SeatsAvailable
+}
+
+// Code returns the subject and course number.
+func (s *Section) Code() (subject, number string) {
+	return s.Subject, s.CourseNumber
+}
+
+// SectionType returns the schedule type, i.e. Lecture.
+func (s *Section) SectionType() string {
+	return s.ScheduleTypeDescription
+}
+
+// Sequence returns the section number.
+func (s *Section) Sequence() string {
+	return s.SequenceNumber
+}
+
+// Instructors returns the names of the section's
+// faculty with the primary instructor first.
+func (s *Section) Instructors() []string {
+	names := make([]string, 0, len(s.Faculty))
+	for _, f := range s.Faculty {
+		if f.PrimaryIndicator {
+			names = append([]string{f.DisplayName}, names...)
+		} else {
+			names = append(names, f.DisplayName)
+		}
+	}
+	return names
+}
+
+// Credits returns the credit hours.
+func (s *Section) Credits() float64 {
+	switch {
+	case s.CreditHours != nil:
+		return *s.CreditHours
+	case s.CreditHourHigh != nil:
+		return *s.CreditHourHigh
+	case s.CreditHourLow != nil:
+		return *s.CreditHourLow
+	}
+	return 0
+}
+
+// Enrollment returns the enrollment numbers.
+func (s *Section) Enrollment() school.Enrollment {
+	return school.Enrollment{
+		Capacity:   s.MaximumEnrollment,
+		Enrolled:   s.Enrolled,
+		Waitlisted: s.WaitCount,
+	}
+}
+
+// MeetingTimes returns the section's meeting times.
+func (s *Section) MeetingTimes() []school.Meeting {
+	meetings := make([]school.Meeting, 0, len(s.MeetingsFaculty))
+	for _, mf := range s.MeetingsFaculty {
+		mt := mf.MeetingTime
+		m := school.Meeting{
+			Days:     mt.Days(),
+			Location: mt.Location(),
+		}
+		m.Start, _ = time.Parse("1504", mt.BeginTime)
+		m.End, _ = time.Parse("1504", mt.EndTime)
+		m.StartDate, _ = time.Parse("01/02/2006", mt.StartDate)
+		m.EndDate, _ = time.Parse("01/02/2006", mt.EndDate)
+		if len(mf.Faculty) > 0 {
+			m.Instructor = mf.Faculty[0].DisplayName
+		} else if names := s.Instructors(); len(names) > 0 {
+			m.Instructor = names[0]
+		}
+		meetings = append(meetings, m)
+	}
+	return meetings
+}
+
+// Schedule is a list of sections from a class search.
+type Schedule struct {
+	sections []*Section
+	crns     map[int]*Section
+}
+
+// NewSchedule creates a schedule from a list of sections.
+func NewSchedule(sections []*Section) *Schedule {
+	s := &Schedule{
+		sections: make([]*Section, 0, len(sections)),
+		crns:     make(map[int]*Section, len(sections)),
+	}
+	for _, sec := range sections {
+		crn := sec.ID()
+		if _, ok := s.crns[crn]; ok || crn == 0 {
+			continue
+		}
+		s.sections = append(s.sections, sec)
+		s.crns[crn] = sec
+	}
+	return s
+}
+
+// Courses returns the sections in the order
+// they were found.
+func (s *Schedule) Courses() []school.Course {
+	courses := make([]school.Course, len(s.sections))
+	for i, sec := range s.sections {
+		courses[i] = sec
+	}
+	return courses
+}
+
+// Get returns the section with the given CRN.
+func (s *Schedule) Get(crn int) school.Course {
+	sec, ok := s.crns[crn]
+	if !ok {
+		return nil
+	}
+	return sec
+}
+
+// Len returns the number of sections.
+func (s *Schedule) Len() int {
+	return len(s.sections)
+}
+
+var (
+	_ school.Schedule  = (*Schedule)(nil)
+	_ school.Course    = (*Section)(nil)
+	_ school.Sequencer = (*Section)(nil)
+)
@@ -0,0 +1,556 @@
+package school
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Filter reports whether a course should be kept.
+type Filter func(Course) bool
+
+// Courses returns the courses that match the filter.
+func (f Filter) Courses(courses []Course) []Course {
+	matched := make([]Course, 0, len(courses))
+	for _, c := range courses {
+		if f(c) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// Groups filters a list of groups. A group is kept with only its
+// matching sections if its course matches. If the course does not
+// match then each matching section becomes its own group.
+func (f Filter) Groups(groups []Group) []Group {
+	matched := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		sections := f.Courses(g.Sections)
+		if f(g.Course) {
+			matched = append(matched, Group{Course: g.Course, Sections: sections})
+			continue
+		}
+		for _, sec := range sections {
+			matched = append(matched, Group{Course: sec})
+		}
+	}
+	return matched
+}
+
+// ParseFilter parses a filter expression. An expression is a
+// list of comparisons joined with "and", "or", "not", and
+// parentheses, i.e.
+//
+//	activity=LECT and days~MW and start>=10:00 and seats>0
+//
+// The operators are =, !=, <, <=, >, >= and ~ (contains) and !~.
+// See FilterFields for the fields that can be compared.
+func ParseFilter(expr string) (Filter, error) {
+	toks, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{toks: toks}
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q in filter", p.toks[p.pos].text)
+	}
+	return f, nil
+}
+
+// FilterFields returns the sorted names of the
+// fields that can be used in a filter expression.
+func FilterFields() []string {
+	names := make([]string, 0, len(filterFields))
+	for name := range filterFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type fieldKind int
+
+const (
+	numberField fieldKind = iota
+	textField
+	daysField
+	clockField
+	boolField
+)
+
+// filterField gets the values of a field from a course.
+// Only the getter for the field's kind is set.
+type filterField struct {
+	kind    fieldKind
+	number  func(Course) float64
+	text    func(Course) []string
+	days    func(Course) []time.Weekday
+	clock   func(Course) (int, bool)
+	boolean func(Course) bool
+}
+
+var filterFields = map[string]filterField{
+	"crn":        {kind: numberField, number: func(c Course) float64 { return float64(c.ID()) }},
+	"units":      {kind: numberField, number: Course.Credits},
+	"seats":      {kind: numberField, number: func(c Course) float64 { return float64(c.SeatsOpen()) }},
+	"capacity":   {kind: numberField, number: func(c Course) float64 { return float64(c.Enrollment().Capacity) }},
+	"enrolled":   {kind: numberField, number: func(c Course) float64 { return float64(c.Enrollment().Enrolled) }},
+	"waitlist":   {kind: numberField, number: func(c Course) float64 { return float64(c.Enrollment().Waitlisted) }},
+	"subject":    {kind: textField, text: func(c Course) []string { s, _ := c.Code(); return []string{s} }},
+	"number":     {kind: textField, text: func(c Course) []string { _, n := c.Code(); return []string{n} }},
+	"title":      {kind: textField, text: func(c Course) []string { return []string{c.Name()} }},
+	"activity":   {kind: textField, text: func(c Course) []string { return []string{c.SectionType()} }},
+	"instructor": {kind: textField, text: Course.Instructors},
+	"building":   {kind: textField, text: buildings},
+	"room":       {kind: textField, text: rooms},
+	"days":       {kind: daysField, days: meetingDays},
+	"start":      {kind: clockField, clock: startTime},
+	"end":        {kind: clockField, clock: endTime},
+	"online":     {kind: boolField, boolean: isOnline},
+}
+
+func rooms(c Course) []string {
+	var list []string
+	for _, m := range c.MeetingTimes() {
+		// empty cells are sometimes only whitespace
+		if strings.TrimSpace(m.Location) != "" {
+			list = append(list, m.Location)
+		}
+	}
+	return list
+}
+
+func buildings(c Course) []string {
+	var list []string
+	for _, room := range rooms(c) {
+		if parts := strings.Fields(room); len(parts) > 0 {
+			list = append(list, parts[0])
+		}
+	}
+	return list
+}
+
+func meetingDays(c Course) []time.Weekday {
+	var days []time.Weekday
+	for _, m := range c.MeetingTimes() {
+		for _, d := range m.Days {
+			if !hasDay(days, d) {
+				days = append(days, d)
+			}
+		}
+	}
+	return days
+}
+
+// startTime returns the earliest start time of the
+// course's meetings in minutes after midnight.
+func startTime(c Course) (int, bool) {
+	start, ok := 0, false
+	for _, m := range c.MeetingTimes() {
+		if m.TBD() {
+			continue
+		}
+		if t := clock(m.Start); !ok || t < start {
+			start, ok = t, true
+		}
+	}
+	return start, ok
+}
+
+// endTime returns the latest end time of the course's
+// meetings in minutes after midnight.
+func endTime(c Course) (int, bool) {
+	end, ok := 0, false
+	for _, m := range c.MeetingTimes() {
+		if m.TBD() {
+			continue
+		}
+		if t := clock(m.End); !ok || t > end {
+			end, ok = t, true
+		}
+	}
+	return end, ok
+}
+
+func clock(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// isOnline returns true if the course has meetings
+// and all of them are online.
+func isOnline(c Course) bool {
+	meetings := c.MeetingTimes()
+	if len(meetings) == 0 {
+		return false
+	}
+	for _, m := range meetings {
+		loc := strings.ToLower(m.Location)
+		if !strings.Contains(loc, "online") &&
+			!strings.Contains(loc, "remote") &&
+			!strings.Contains(loc, "internet") {
+			return false
+		}
+	}
+	return true
+}
+
+func hasDay(days []time.Weekday, d time.Weekday) bool {
+	for _, day := range days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
+
+// compare builds the filter for one comparison.
+func compare(name, op, value string) (Filter, error) {
+	field, ok := filterFields[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown filter field %q (fields: %s)",
+			name, strings.Join(FilterFields(), ", "))
+	}
+	var f Filter
+	switch field.kind {
+	case numberField:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs a number, got %q", name, value)
+		}
+		cmp, err := ordered(op)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		f = func(c Course) bool {
+			v := field.number(c)
+			return cmp(sign(v - n))
+		}
+	case clockField:
+		t, err := parseClock(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		cmp, err := ordered(op)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		f = func(c Course) bool {
+			v, ok := field.clock(c)
+			return ok && cmp(sign(float64(v-t)))
+		}
+	case textField:
+		value = strings.ToLower(value)
+		var match func(string) bool
+		switch strings.TrimPrefix(op, "!") {
+		case "=":
+			match = func(s string) bool { return strings.ToLower(s) == value }
+		case "~":
+			match = func(s string) bool { return strings.Contains(strings.ToLower(s), value) }
+		default:
+			return nil, fmt.Errorf("%s can only use =, !=, ~, or !~", name)
+		}
+		f = func(c Course) bool {
+			for _, s := range field.text(c) {
+				if match(s) {
+					return true
+				}
+			}
+			return false
+		}
+	case daysField:
+		days := parseDays(value)
+		if len(days) == 0 {
+			return nil, fmt.Errorf("%s needs days like MWF or TR, got %q", name, value)
+		}
+		switch strings.TrimPrefix(op, "!") {
+		case "=":
+			f = func(c Course) bool {
+				have := field.days(c)
+				return len(have) == len(days) && containsDays(have, days)
+			}
+		case "~":
+			f = func(c Course) bool { return containsDays(field.days(c), days) }
+		default:
+			return nil, fmt.Errorf("%s can only use =, !=, ~, or !~", name)
+		}
+	case boolField:
+		b, err := parseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		if op != "=" && op != "!=" {
+			return nil, fmt.Errorf("%s can only use = or !=", name)
+		}
+		f = func(c Course) bool { return field.boolean(c) == b }
+	}
+	if field.kind != numberField && field.kind != clockField && strings.HasPrefix(op, "!") {
+		return not(f), nil
+	}
+	return f, nil
+}
+
+// ordered returns a function that checks the sign
+// of the difference between two values.
+func ordered(op string) (func(int) bool, error) {
+	switch op {
+	case "=":
+		return func(s int) bool { return s == 0 }, nil
+	case "!=":
+		return func(s int) bool { return s != 0 }, nil
+	case "<":
+		return func(s int) bool { return s < 0 }, nil
+	case "<=":
+		return func(s int) bool { return s <= 0 }, nil
+	case ">":
+		return func(s int) bool { return s > 0 }, nil
+	case ">=":
+		return func(s int) bool { return s >= 0 }, nil
+	}
+	return nil, fmt.Errorf("operator %q is not allowed", op)
+}
+
+func sign(f float64) int {
+	switch {
+	case f < 0:
+		return -1
+	case f > 0:
+		return 1
+	}
+	return 0
+}
+
+func not(f Filter) Filter {
+	return func(c Course) bool { return !f(c) }
+}
+
+func containsDays(have, want []time.Weekday) bool {
+	for _, d := range want {
+		if !hasDay(have, d) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseDays parses days like "MWF", "TR", or "TuTh".
+func parseDays(s string) []time.Weekday {
+	var (
+		days   []time.Weekday
+		prefix = []struct {
+			abbr string
+			day  time.Weekday
+		}{
+			{"SU", time.Sunday},
+			{"SA", time.Saturday},
+			{"TU", time.Tuesday},
+			{"TH", time.Thursday},
+			{"M", time.Monday},
+			{"T", time.Tuesday},
+			{"W", time.Wednesday},
+			{"R", time.Thursday},
+			{"F", time.Friday},
+			{"S", time.Saturday},
+			{"U", time.Sunday},
+		}
+	)
+	s = strings.ToUpper(s)
+outer:
+	for len(s) > 0 {
+		for _, p := range prefix {
+			if strings.HasPrefix(s, p.abbr) {
+				if !hasDay(days, p.day) {
+					days = append(days, p.day)
+				}
+				s = s[len(p.abbr):]
+				continue outer
+			}
+		}
+		return nil
+	}
+	return days
+}
+
+// parseClock parses a time of day into minutes after midnight.
+func parseClock(s string) (int, error) {
+	s = strings.ToLower(strings.Replace(s, " ", "", -1))
+	for _, layout := range []string{"15:04", "3:04pm", "3pm", "15"} {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return clock(t), nil
+		}
+	}
+	return 0, fmt.Errorf("bad time %q, use a time like 10:00 or 2:30pm", s)
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "y", "1":
+		return true, nil
+	case "false", "no", "n", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not true or false", s)
+}
+
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	opToken
+	lparenToken
+	rparenToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+var filterOps = []string{"<=", ">=", "!=", "!~", "=", "<", ">", "~"}
+
+func lexFilter(s string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+			continue
+		case c == '(':
+			toks = append(toks, token{lparenToken, "("})
+			i++
+			continue
+		case c == ')':
+			toks = append(toks, token{rparenToken, ")"})
+			i++
+			continue
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in filter")
+			}
+			toks = append(toks, token{wordToken, s[i+1 : i+1+end]})
+			i += end + 2
+			continue
+		}
+		if op := opAt(s[i:]); op != "" {
+			toks = append(toks, token{opToken, op})
+			i += len(op)
+			continue
+		}
+		start := i
+		for i < len(s) && !unicode.IsSpace(rune(s[i])) &&
+			s[i] != '(' && s[i] != ')' && opAt(s[i:]) == "" {
+			i++
+		}
+		toks = append(toks, token{wordToken, s[start:i]})
+	}
+	return toks, nil
+}
+
+func opAt(s string) string {
+	for _, op := range filterOps {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+type filterParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *filterParser) next() *token {
+	if p.pos >= len(p.toks) {
+		return nil
+	}
+	t := &p.toks[p.pos]
+	p.pos++
+	return t
+}
+
+func (p *filterParser) keyword(word string) bool {
+	if p.pos < len(p.toks) && p.toks[p.pos].kind == wordToken &&
+		strings.EqualFold(p.toks[p.pos].text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) or() (Filter, error) {
+	f, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left := f
+		f = func(c Course) bool { return left(c) || right(c) }
+	}
+	return f, nil
+}
+
+func (p *filterParser) and() (Filter, error) {
+	f, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left := f
+		f = func(c Course) bool { return left(c) && right(c) }
+	}
+	return f, nil
+}
+
+func (p *filterParser) unary() (Filter, error) {
+	if p.keyword("not") {
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return not(f), nil
+	}
+	t := p.next()
+	if t == nil {
+		return nil, fmt.Errorf("filter ended early")
+	}
+	switch t.kind {
+	case lparenToken:
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t = p.next(); t == nil || t.kind != rparenToken {
+			return nil, fmt.Errorf("missing ')' in filter")
+		}
+		return f, nil
+	case wordToken:
+		op, value := p.next(), p.next()
+		if op == nil || op.kind != opToken {
+			return nil, fmt.Errorf("expected an operator after %q", t.text)
+		}
+		if value == nil || value.kind != wordToken {
+			return nil, fmt.Errorf("expected a value after %s%s", t.text, op.text)
+		}
+		return compare(t.text, op.text, value.text)
+	}
+	return nil, fmt.Errorf("unexpected %q in filter", t.text)
+}
@@ -0,0 +1,152 @@
+package school
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCourse struct {
+	crn, seats  int
+	subj, num   string
+	kind        string
+	units       float64
+	instructors []string
+	meetings    []Meeting
+	enrollment  Enrollment
+}
+
+func (c *testCourse) ID() int                 { return c.crn }
+func (c *testCourse) Name() string            { return c.subj + " " + c.num }
+func (c *testCourse) SeatsOpen() int          { return c.seats }
+func (c *testCourse) Code() (string, string)  { return c.subj, c.num }
+func (c *testCourse) SectionType() string     { return c.kind }
+func (c *testCourse) Instructors() []string   { return c.instructors }
+func (c *testCourse) Credits() float64        { return c.units }
+func (c *testCourse) Enrollment() Enrollment  { return c.enrollment }
+func (c *testCourse) MeetingTimes() []Meeting { return c.meetings }
+
+func at(h, m int) time.Time {
+	return time.Date(0, 1, 1, h, m, 0, 0, time.UTC)
+}
+
+func TestFilter(t *testing.T) {
+	lect := &testCourse{
+		crn: 1, seats: 5, subj: "CSE", num: "100", kind: "LECT", units: 4,
+		instructors: []string{"Doe, John"},
+		enrollment:  Enrollment{Capacity: 100, Enrolled: 95, Waitlisted: 2},
+		meetings: []Meeting{{
+			Days:     []time.Weekday{time.Monday, time.Wednesday},
+			Start:    at(10, 30),
+			End:      at(11, 45),
+			Location: "COB 110",
+		}},
+	}
+	lab := &testCourse{
+		crn: 2, subj: "CSE", num: "100", kind: "LAB",
+		meetings: []Meeting{{
+			Days:     []time.Weekday{time.Friday},
+			Start:    at(8, 0),
+			End:      at(9, 50),
+			Location: "ONLINE",
+		}},
+	}
+	tbd := &testCourse{
+		crn: 3, subj: "MATH", num: "21", kind: "LECT", units: 4, seats: 1,
+		// from an &nbsp; cell
+		meetings: []Meeting{{Location: "\u00a0"}},
+	}
+	courses := []Course{lect, lab, tbd}
+
+	for _, tc := range []struct {
+		expr string
+		want []int
+	}{
+		{"activity=LECT and days~MW and start>=10:00 and seats>0", []int{1}},
+		{"activity=lect", []int{1, 3}},
+		{"activity!=lect", []int{2}},
+		{"days=MW", []int{1}},
+		{"days~W or days~F", []int{1, 2}},
+		{"not days~M", []int{2, 3}},
+		{"days!~M", []int{2, 3}},
+		{"start<9am", []int{2}},
+		{"end>=11:45", []int{1}},
+		{"units=4 and subject=cse", []int{1}},
+		{"instructor~doe", []int{1}},
+		{"building=cob", []int{1}},
+		{"building~o", []int{1, 2}},
+		{"room~' '", []int{1}},
+		{"online=true", []int{2}},
+		{"online=no and (seats>0 or waitlist>0)", []int{1, 3}},
+		{"capacity>=100 and enrolled<96", []int{1}},
+		{"title~'cse 1'", []int{1, 2}},
+		{"crn != 2", []int{1, 3}},
+	} {
+		f, err := ParseFilter(tc.expr)
+		if err != nil {
+			t.Errorf("%q: %v", tc.expr, err)
+			continue
+		}
+		var got []int
+		for _, c := range f.Courses(courses) {
+			got = append(got, c.ID())
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%q: got %v; want %v", tc.expr, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%q: got %v; want %v", tc.expr, got, tc.want)
+				break
+			}
+		}
+	}
+
+	for _, expr := range []string{
+		"",
+		"seats",
+		"seats>",
+		"color=red",
+		"seats>many",
+		"activity>LECT",
+		"days=XYZ",
+		"start>noon",
+		"online~yes",
+		"(seats>0",
+		"seats>0 seats<2",
+		"title='cse",
+	} {
+		if _, err := ParseFilter(expr); err == nil {
+			t.Errorf("expected an error for %q", expr)
+		}
+	}
+	_, err := ParseFilter("color=red")
+	if err == nil || !strings.Contains(err.Error(), "activity") {
+		t.Errorf("unknown fields should list the fields: %v", err)
+	}
+}
+
+func TestFilterGroups(t *testing.T) {
+	lect := &testCourse{crn: 1, kind: "LECT", seats: 1}
+	groups := []Group{{
+		Course: lect,
+		Sections: []Course{
+			&testCourse{crn: 2, kind: "LAB", seats: 0},
+			&testCourse{crn: 3, kind: "LAB", seats: 2},
+		},
+	}}
+	f, err := ParseFilter("seats>0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := f.Groups(groups)
+	if len(got) != 1 || len(got[0].Sections) != 1 || got[0].Sections[0].ID() != 3 {
+		t.Errorf("closed sections should be removed: %+v", got)
+	}
+	f, _ = ParseFilter("activity=lab")
+	got = f.Groups(groups)
+	if len(got) != 2 || got[0].Course.ID() != 2 || got[1].Course.ID() != 3 {
+		t.Errorf("sections should be their own groups: %+v", got)
+	}
+}
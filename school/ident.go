@@ -0,0 +1,134 @@
+package school
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Sequencer is a course that has a section number,
+// i.e. the "02" in CSE-100-02.
+type Sequencer interface {
+	Sequence() string
+}
+
+// CourseIdent identifies a set of sections by subject and
+// course number with an optional section number or activity.
+// Section numbers may have an activity letter at the end,
+// i.e. the "02L" in CSE-100-02L.
+type CourseIdent struct {
+	Subject  string
+	Number   string
+	Section  string
+	Activity string
+}
+
+// ParseCourseIdent parses a course identifier such as
+// "CSE 100", "CSE-100-02L", "MATH24", or "MATH 24 LAB". The
+// course number is the first part that starts with a digit
+// so subjects can have spaces, i.e. "EL ENG 16A".
+func ParseCourseIdent(s string) (CourseIdent, error) {
+	var (
+		id    CourseIdent
+		subj  []string
+		parts = splitCode(strings.FieldsFunc(s, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '-'
+		}))
+	)
+	for i, p := range parts {
+		if !unicode.IsDigit(rune(p[0])) {
+			subj = append(subj, p)
+			continue
+		}
+		id.Number = p
+		for _, rest := range parts[i+1:] {
+			if unicode.IsDigit(rune(rest[0])) && id.Section == "" {
+				id.Section = rest
+			} else if id.Activity == "" {
+				id.Activity = rest
+			} else {
+				return id, errors.New("too many parts in course " + s)
+			}
+		}
+		break
+	}
+	if len(subj) == 0 || id.Number == "" {
+		return id, errors.New("course " + s + " needs a subject and number, i.e. 'CSE 100'")
+	}
+	id.Subject = strings.ToUpper(strings.Join(subj, " "))
+	return id, nil
+}
+
+// Match returns true if the course is one of the identified
+// sections. Courses that are not a Sequencer never match an
+// identifier with a section number.
+func (id CourseIdent) Match(c Course) bool {
+	subj, num := c.Code()
+	if !strings.EqualFold(subj, id.Subject) || !sameNumber(num, id.Number) {
+		return false
+	}
+	if id.Activity != "" && !strings.HasPrefix(
+		strings.ToLower(c.SectionType()),
+		strings.ToLower(id.Activity),
+	) {
+		return false
+	}
+	if id.Section != "" {
+		seq, ok := c.(Sequencer)
+		return ok && sameSection(seq.Sequence(), id.Section)
+	}
+	return true
+}
+
+func (id CourseIdent) String() string {
+	s := id.Subject + " " + id.Number
+	if id.Section != "" {
+		s += "-" + id.Section
+	}
+	if id.Activity != "" {
+		s += " " + id.Activity
+	}
+	return s
+}
+
+// sameNumber compares course or section numbers
+// ignoring case and leading zeros.
+func sameNumber(a, b string) bool {
+	return strings.EqualFold(
+		strings.TrimLeft(strings.TrimSpace(a), "0"),
+		strings.TrimLeft(strings.TrimSpace(b), "0"),
+	)
+}
+
+// sameSection compares section numbers. The activity letter
+// at the end of a section number is only compared if both
+// of them have one so "02" is the same as "02L".
+func sameSection(a, b string) bool {
+	if sameNumber(a, b) {
+		return true
+	}
+	ta, tb := strings.TrimRightFunc(a, unicode.IsLetter), strings.TrimRightFunc(b, unicode.IsLetter)
+	if ta != a && tb != b {
+		return false
+	}
+	return sameNumber(ta, tb)
+}
+
+// splitCode splits a subject and number that are
+// written together, i.e. "MATH24" becomes "MATH" "24".
+func splitCode(parts []string) []string {
+	for i, p := range parts {
+		if unicode.IsDigit(rune(p[0])) {
+			break
+		}
+		j := strings.IndexFunc(p, unicode.IsDigit)
+		if j < 0 {
+			continue
+		}
+		split := make([]string, 0, len(parts)+1)
+		split = append(split, parts[:i]...)
+		split = append(split, p[:j], p[j:])
+		return append(split, parts[i+1:]...)
+	}
+	return parts
+}
@@ -0,0 +1,79 @@
+package school
+
+import "testing"
+
+type seqCourse struct {
+	testCourse
+	seq string
+}
+
+func (c *seqCourse) Sequence() string { return c.seq }
+
+func TestParseCourseIdent(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want CourseIdent
+	}{
+		{"CSE 100", CourseIdent{Subject: "CSE", Number: "100"}},
+		{"cse-100-02", CourseIdent{Subject: "CSE", Number: "100", Section: "02"}},
+		{"MATH 24 LAB", CourseIdent{Subject: "MATH", Number: "24", Activity: "LAB"}},
+		{"MATH 24 02 lab", CourseIdent{Subject: "MATH", Number: "24", Section: "02", Activity: "lab"}},
+		{"EL ENG 16A", CourseIdent{Subject: "EL ENG", Number: "16A"}},
+		{"CSE-100-02L", CourseIdent{Subject: "CSE", Number: "100", Section: "02L"}},
+		{"MATH24 02D", CourseIdent{Subject: "MATH", Number: "24", Section: "02D"}},
+	} {
+		id, err := ParseCourseIdent(tc.in)
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		if id != tc.want {
+			t.Errorf("%q: got %+v; want %+v", tc.in, id, tc.want)
+		}
+	}
+	for _, in := range []string{"", "CSE", "100", "CSE 100 LAB 02 DISC"} {
+		if _, err := ParseCourseIdent(in); err == nil {
+			t.Errorf("expected an error for %q", in)
+		}
+	}
+	if id, _ := ParseCourseIdent("cse-100-2 lab"); id.String() != "CSE 100-2 lab" {
+		t.Errorf("wrong string: %q", id.String())
+	}
+}
+
+func TestCourseIdentMatch(t *testing.T) {
+	lect := &seqCourse{testCourse{subj: "CSE", num: "100", kind: "LECT"}, "01"}
+	lab := &seqCourse{testCourse{subj: "CSE", num: "100", kind: "LAB"}, "02"}
+	other := &testCourse{subj: "CSE", num: "100", kind: "LAB"}
+	lab3 := &seqCourse{testCourse{subj: "CSE", num: "100", kind: "LAB"}, "03L"}
+	for _, tc := range []struct {
+		id   string
+		c    Course
+		want bool
+	}{
+		{"CSE 100", lect, true},
+		{"cse 0100", lab, true},
+		{"CSE 10", lab, false},
+		{"MATH 100", lab, false},
+		{"CSE-100-02", lab, true},
+		{"CSE-100-2", lab, true},
+		{"CSE-100-02", lect, false},
+		{"CSE 100 LAB", lab, true},
+		{"CSE 100 LAB", lect, false},
+		{"CSE 100 LAB", other, true},
+		{"CSE-100-02", other, false},
+		{"CSE-100-03L", lab3, true},
+		{"CSE-100-03", lab3, true},
+		{"CSE 100 3 LAB", lab3, true},
+		{"CSE-100-03D", lab3, false},
+		{"CSE-100-03L", lect, false},
+	} {
+		id, err := ParseCourseIdent(tc.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := id.Match(tc.c); got != tc.want {
+			t.Errorf("%q matching %s %s: got %v", tc.id, tc.c.Name(), tc.c.SectionType(), got)
+		}
+	}
+}
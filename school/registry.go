@@ -0,0 +1,142 @@
+package school
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Config is a set of config variables for
+// finding a school schedule.
+type Config struct {
+	Term string
+	Year int
+	// FilterClosed, if true, will filter out any courses
+	// that do not have seats open
+	FilterClosed bool
+	CourseName   string
+}
+
+// Capability is a set of features that a provider supports.
+type Capability uint
+
+const (
+	// Seats means that courses report the number of open seats.
+	Seats Capability = 1 << iota
+	// Meetings means that courses have meeting times.
+	Meetings
+	// SectionLinks means that labs and discussions
+	// are linked to their lecture.
+	SectionLinks
+	// Offerings means that the provider can check which
+	// terms and subjects are offered.
+	Offerings
+)
+
+var capabilityNames = []string{"seats", "meetings", "section-links", "offerings"}
+
+// Has returns true if c has all of the capabilities in o.
+func (c Capability) Has(o Capability) bool {
+	return c&o == o
+}
+
+func (c Capability) String() string {
+	names := make([]string, 0, len(capabilityNames))
+	for i, name := range capabilityNames {
+		if c.Has(1 << uint(i)) {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// Provider is a school that schedules can be fetched from.
+type Provider struct {
+	// Name is the name used to look up the provider.
+	Name    string
+	Aliases []string
+	// Title is the full name of the school.
+	Title   string
+	BaseURL string
+	// Terms maps term names to the school's term codes.
+	Terms        map[string]string
+	Capabilities Capability
+	// TimeZone is the name of the time zone that the schedule's
+	// times are in, i.e. America/Los_Angeles. It may be empty.
+	TimeZone string
+
+	// New will fetch a schedule. The context can be
+	// used to cancel a fetch that is taking too long.
+	New func(context.Context, *Config) (Schedule, error)
+	// Check will make sure the config is offered before
+	// fetching a schedule. Check may be nil.
+	Check func(context.Context, *Config) error
+}
+
+// TermNames returns the sorted names of the provider's terms.
+func (p *Provider) TermNames() []string {
+	names := make([]string, 0, len(p.Terms))
+	for name := range p.Terms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]*Provider)
+)
+
+// ErrUnknownSchool is returned when there
+// is no provider for a school.
+var ErrUnknownSchool = errors.New("unknown school")
+
+// Register will make a provider available by its name
+// and aliases. Register panics if a name is registered
+// twice or if the provider has no New function.
+func Register(p *Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if p == nil || p.New == nil {
+		panic("school: Register provider is nil")
+	}
+	names := append([]string{p.Name}, p.Aliases...)
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if _, dup := providers[name]; dup {
+			panic("school: Register called twice for " + name)
+		}
+		providers[name] = p
+	}
+}
+
+// Lookup will find a provider by name or alias.
+func Lookup(name string) (*Provider, error) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownSchool, name)
+	}
+	return p, nil
+}
+
+// Providers returns all the registered providers sorted by name.
+func Providers() []*Provider {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	list := make([]*Provider, 0, len(providers))
+	for name, p := range providers {
+		if name == strings.ToLower(p.Name) {
+			list = append(list, p)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
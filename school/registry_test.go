@@ -0,0 +1,53 @@
+package school
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	p := &Provider{
+		Name:         "testschool",
+		Aliases:      []string{"TS"},
+		Capabilities: Seats | Offerings,
+		New:          func(context.Context, *Config) (Schedule, error) { return nil, nil },
+	}
+	Register(p)
+	defer func() {
+		delete(providers, "testschool")
+		delete(providers, "ts")
+	}()
+
+	for _, name := range []string{"testschool", "TestSchool", "ts"} {
+		found, err := Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found != p {
+			t.Errorf("wrong provider for %q", name)
+		}
+	}
+	if _, err := Lookup("nowhere"); !errors.Is(err, ErrUnknownSchool) {
+		t.Errorf("expected unknown school error, got %v", err)
+	}
+	n := 0
+	for _, pr := range Providers() {
+		if pr == p {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("provider should be listed once, got %d", n)
+	}
+	if s := p.Capabilities.String(); s != "seats,offerings" {
+		t.Errorf("wrong capabilities string: %q", s)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when registering twice")
+		}
+	}()
+	Register(&Provider{Name: "ts", New: p.New})
+}
@@ -0,0 +1,137 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+// Cache is an on-disk cache of schedules. Schedules are
+// keyed by school, term, year, subject, and whether only
+// open courses were requested.
+type Cache struct {
+	dir string
+	// TTL is how long a cached schedule is used
+	// before a new one is fetched.
+	TTL time.Duration
+	// MaxAge is the oldest a cached schedule can be
+	// if fetching a new one fails. Zero means a
+	// failed fetch is never covered by the cache.
+	MaxAge time.Duration
+	// Refresh will always fetch a new schedule, the
+	// new schedule is still saved to the cache.
+	Refresh bool
+}
+
+// OpenCache will open a cache in a directory and
+// create the directory if it does not exist.
+func OpenCache(dir string, ttl time.Duration) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir, TTL: ttl}, nil
+}
+
+// Dir returns the cache's directory.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Wrap returns a copy of a provider that gets its schedules
+// from the cache. The provider's Check is skipped when
+// there is a fresh schedule in the cache.
+func (c *Cache) Wrap(p *school.Provider) *school.Provider {
+	cp := *p
+	cp.New = func(ctx context.Context, conf *school.Config) (school.Schedule, error) {
+		return c.Get(ctx, p, conf)
+	}
+	if p.Check != nil {
+		cp.Check = func(ctx context.Context, conf *school.Config) error {
+			if c.fresh(p.Name, conf) != nil {
+				return nil
+			}
+			return p.Check(ctx, conf)
+		}
+	}
+	return &cp
+}
+
+// Get will get a schedule from the cache if it is fresh,
+// otherwise the schedule is fetched from the provider and
+// saved. If the fetch fails then a cached schedule that is
+// within MaxAge is returned and marked as stale.
+func (c *Cache) Get(ctx context.Context, p *school.Provider, conf *school.Config) (school.Schedule, error) {
+	if snap := c.fresh(p.Name, conf); snap != nil {
+		return snap, nil
+	}
+	sched, err := p.New(ctx, conf)
+	if err != nil {
+		snap, e := c.Load(p.Name, conf)
+		if e != nil || c.MaxAge <= 0 || snap.Age() > c.MaxAge {
+			return nil, err
+		}
+		snap.stale = true
+		return snap, nil
+	}
+	snap := NewSnapshot(sched)
+	// the schedule is still good if it can't be cached
+	if err = c.Save(p.Name, conf, snap); err != nil {
+		log.Printf("could not cache schedule: %v\n", err)
+	}
+	return snap, nil
+}
+
+// Load will read a schedule from the cache no matter how old it is.
+func (c *Cache) Load(name string, conf *school.Config) (*Snapshot, error) {
+	return ReadSnapshot(c.file(name, conf))
+}
+
+// Save will write a schedule to the cache.
+func (c *Cache) Save(name string, conf *school.Config, snap *Snapshot) error {
+	return snap.WriteFile(c.file(name, conf))
+}
+
+func (c *Cache) fresh(name string, conf *school.Config) *Snapshot {
+	if c.Refresh || c.TTL <= 0 {
+		return nil
+	}
+	snap, err := c.Load(name, conf)
+	if err != nil || snap.Age() > c.TTL {
+		return nil
+	}
+	return snap
+}
+
+func (c *Cache) file(name string, conf *school.Config) string {
+	subject := conf.CourseName
+	if subject == "" {
+		subject = "all"
+	}
+	open := "all"
+	if conf.FilterClosed {
+		open = "open"
+	}
+	return filepath.Join(c.dir, cacheKey(name), fmt.Sprintf(
+		"%d-%s-%s-%s.json",
+		conf.Year, cacheKey(conf.Term), cacheKey(subject), open,
+	))
+}
+
+// cacheKey makes s safe to use in a file name.
+func cacheKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '_'
+	}, s)
+}
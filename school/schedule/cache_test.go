@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "edu-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cache, err := OpenCache(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		fetches, checks int
+		down            bool
+		sched           = testSchedule()
+	)
+	p := cache.Wrap(&school.Provider{
+		Name: "Test School",
+		New: func(context.Context, *school.Config) (school.Schedule, error) {
+			if down {
+				return nil, errors.New("site is down")
+			}
+			fetches++
+			return sched, nil
+		},
+		Check: func(context.Context, *school.Config) error {
+			checks++
+			return nil
+		},
+	})
+	ctx := context.Background()
+	conf := &school.Config{Year: 2021, Term: "spring", CourseName: "EL ENG"}
+
+	for i := 0; i < 2; i++ {
+		if err = p.Check(ctx, conf); err != nil {
+			t.Fatal(err)
+		}
+		s, err := p.New(ctx, conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Len() != 3 || s.Get(2).Name() != "Lab" {
+			t.Fatalf("wrong schedule: %d courses", s.Len())
+		}
+	}
+	if fetches != 1 || checks != 1 {
+		t.Errorf("second call should use the cache: %d fetches, %d checks", fetches, checks)
+	}
+	if _, err = os.Stat(cache.file("Test School", conf)); err != nil {
+		t.Error(err)
+	}
+
+	s, _ := p.New(ctx, &school.Config{Year: 2021, Term: "spring", FilterClosed: true})
+	if fetches != 2 || s == nil {
+		t.Errorf("a different key should not be cached: %d fetches", fetches)
+	}
+
+	cache.Refresh = true
+	if _, err = p.New(ctx, conf); err != nil || fetches != 3 {
+		t.Errorf("refresh should skip the cache: %d fetches", fetches)
+	}
+
+	down = true
+	if _, err = p.New(ctx, conf); err == nil {
+		t.Error("stale schedules should not be used without a max age")
+	}
+	cache.MaxAge = time.Hour
+	s, err = p.New(ctx, conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap, ok := s.(*Snapshot); !ok || !snap.Stale() {
+		t.Error("expected a stale snapshot")
+	}
+	groups := s.(school.Grouper).SectionGroups()
+	if len(groups) != 1 || len(groups[0].Sections) != 2 {
+		t.Errorf("groups were not cached: %+v", groups)
+	}
+	m := s.Get(1).MeetingTimes()
+	if len(m) != 1 || m[0].Days[0] != time.Tuesday || m[0].Start.Hour() != 9 {
+		t.Errorf("wrong meetings: %+v", m)
+	}
+	// the cache can't be written to
+	down = false
+	if err = os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(dir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = p.New(ctx, conf); err != nil || s.Len() != 3 {
+		t.Errorf("a schedule that can't be cached should still be returned: %v", err)
+	}
+}
+
+func testSchedule() *Snapshot {
+	lect := &Course{
+		CourseID: 1, Title: "Lecture", Subject: "EL ENG", Number: "16A",
+		Seats: 3, Enrolled: school.Enrollment{Capacity: 10, Enrolled: 7},
+		Meetings: []school.Meeting{{
+			Days:  []time.Weekday{time.Tuesday, time.Thursday},
+			Start: time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC),
+			End:   time.Date(0, 1, 1, 11, 0, 0, 0, time.UTC),
+		}},
+	}
+	s := &Snapshot{Groups: []SnapshotGroup{{
+		Course: lect,
+		Sections: []*Course{
+			{CourseID: 2, Title: "Lab"},
+			{CourseID: 3, Title: "Discussion"},
+		},
+	}}}
+	s.index()
+	return s
+}
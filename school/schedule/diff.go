@@ -0,0 +1,164 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+// ChangeKind is the type of change found between two schedules.
+type ChangeKind int
+
+// The kinds of change
+const (
+	// Added is a course that is only in the new schedule
+	Added ChangeKind = iota
+	// Removed is a course that is only in the old schedule
+	Removed
+	// Modified is a course that is in both schedules but has changed
+	Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	default:
+		return "ChangeKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Change is a difference in one course between two schedules.
+type Change struct {
+	Kind ChangeKind
+	ID   int
+	// Old is nil for added courses, New is
+	// nil for removed courses.
+	Old, New school.Course
+	// Fields is the list of changed fields, it is
+	// only set for modified courses.
+	Fields []FieldChange
+}
+
+// FieldChange is a change in one field of a course.
+type FieldChange struct {
+	Field    string
+	Old, New string
+}
+
+// Diff will find all of the courses that were added, removed,
+// or changed between two schedules. The changes are sorted by id.
+func Diff(old, new school.Schedule) []Change {
+	changes := make([]Change, 0)
+	for _, o := range old.Courses() {
+		n := new.Get(o.ID())
+		if n == nil {
+			changes = append(changes, Change{Kind: Removed, ID: o.ID(), Old: o})
+			continue
+		}
+		fields := diffCourse(o, n)
+		if len(fields) > 0 {
+			changes = append(changes, Change{
+				Kind:   Modified,
+				ID:     o.ID(),
+				Old:    o,
+				New:    n,
+				Fields: fields,
+			})
+		}
+	}
+	for _, n := range new.Courses() {
+		if old.Get(n.ID()) == nil {
+			changes = append(changes, Change{Kind: Added, ID: n.ID(), New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ID < changes[j].ID
+	})
+	return changes
+}
+
+func diffCourse(o, n school.Course) []FieldChange {
+	var (
+		fields = make([]FieldChange, 0)
+		oe, ne = o.Enrollment(), n.Enrollment()
+	)
+	add := func(name, old, new string) {
+		if old != new {
+			fields = append(fields, FieldChange{Field: name, Old: old, New: new})
+		}
+	}
+	add("seats", strconv.Itoa(o.SeatsOpen()), strconv.Itoa(n.SeatsOpen()))
+	add("capacity", strconv.Itoa(oe.Capacity), strconv.Itoa(ne.Capacity))
+	add("enrolled", strconv.Itoa(oe.Enrolled), strconv.Itoa(ne.Enrolled))
+	add("waitlisted", strconv.Itoa(oe.Waitlisted), strconv.Itoa(ne.Waitlisted))
+	add("instructor", strings.Join(o.Instructors(), ", "), strings.Join(n.Instructors(), ", "))
+	add("room", joinMeetings(o, meetingRoom), joinMeetings(n, meetingRoom))
+	add("time", joinMeetings(o, meetingTime), joinMeetings(n, meetingTime))
+	add("exam", examString(o), examString(n))
+	return fields
+}
+
+func joinMeetings(c school.Course, fn func(*school.Meeting) string) string {
+	meetings := c.MeetingTimes()
+	parts := make([]string, len(meetings))
+	for i := range meetings {
+		parts[i] = fn(&meetings[i])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func meetingRoom(m *school.Meeting) string {
+	return strings.TrimSpace(m.Location)
+}
+
+func meetingTime(m *school.Meeting) string {
+	if m.TBD() {
+		return strings.TrimSpace(dayString(m.Days) + " TBD")
+	}
+	return fmt.Sprintf("%s %s-%s", dayString(m.Days),
+		m.Start.Format("3:04pm"), m.End.Format("3:04pm"))
+}
+
+func examString(c school.Course) string {
+	ex, ok := c.(school.Examiner)
+	if !ok {
+		return ""
+	}
+	e := ex.FinalExam()
+	if e == nil {
+		return ""
+	}
+	when := "TBD"
+	if !e.TBD() {
+		when = e.Start.Format("3:04pm") + "-" + e.End.Format("3:04pm")
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s",
+		e.StartDate.Format("Jan 2"), when, strings.TrimSpace(e.Location)))
+}
+
+var weekdayLetters = map[time.Weekday]string{
+	time.Sunday:    "U",
+	time.Monday:    "M",
+	time.Tuesday:   "T",
+	time.Wednesday: "W",
+	time.Thursday:  "R",
+	time.Friday:    "F",
+	time.Saturday:  "S",
+}
+
+func dayString(days []time.Weekday) string {
+	var b strings.Builder
+	for _, d := range days {
+		b.WriteString(weekdayLetters[d])
+	}
+	return b.String()
+}
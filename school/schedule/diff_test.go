@@ -0,0 +1,90 @@
+package schedule
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+func TestDiff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "edu-diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	old := testSchedule()
+	old.courses[2].Exam = &school.Meeting{
+		Days:      []time.Weekday{time.Monday},
+		Start:     time.Date(0, 1, 1, 15, 0, 0, 0, time.UTC),
+		End:       time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		Location:  "Wheeler 150",
+		StartDate: time.Date(2021, time.December, 13, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2021, time.December, 13, 0, 0, 0, 0, time.UTC),
+	}
+	// round trip through a file to make sure
+	// snapshots keep everything
+	file := filepath.Join(dir, "snapshots", "2021-fall.json")
+	if err = old.WriteFile(file); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := ReadSnapshot(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes := Diff(old, snap); len(changes) != 0 {
+		t.Fatalf("a schedule should not be different from its snapshot: %+v", changes)
+	}
+
+	lect := snap.courses[1]
+	lect.Seats = 0
+	lect.Enrolled.Enrolled = 10
+	lect.Meetings[0].Location = "Cory 521"
+	lect.Meetings[0].Start = lect.Meetings[0].Start.Add(time.Hour)
+	exam := snap.courses[2].Exam
+	exam.StartDate = exam.StartDate.AddDate(0, 0, 1)
+	exam.EndDate = exam.StartDate
+	snap.Groups[0].Sections = snap.Groups[0].Sections[:1]
+	snap.Groups = append(snap.Groups, SnapshotGroup{Course: &Course{CourseID: 4, Title: "New"}})
+	snap.index()
+
+	changes := Diff(old, snap)
+	expected := []struct {
+		id     int
+		kind   ChangeKind
+		fields []string
+	}{
+		{1, Modified, []string{"seats", "enrolled", "room", "time"}},
+		{2, Modified, []string{"exam"}},
+		{3, Removed, nil},
+		{4, Added, nil},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("wrong number of changes: got %d; want %d", len(changes), len(expected))
+	}
+	for i, exp := range expected {
+		c := changes[i]
+		if c.ID != exp.id || c.Kind != exp.kind {
+			t.Errorf("wrong change: got %d %v; want %d %v", c.ID, c.Kind, exp.id, exp.kind)
+			continue
+		}
+		if len(c.Fields) != len(exp.fields) {
+			t.Errorf("wrong fields for %d: %v", c.ID, c.Fields)
+			continue
+		}
+		for j, f := range exp.fields {
+			if c.Fields[j].Field != f {
+				t.Errorf("wrong field for %d: got %s; want %s", c.ID, c.Fields[j].Field, f)
+			}
+		}
+	}
+	if f := changes[0].Fields[3]; f.Old != "TR 9:30am-11:00am" || f.New != "TR 10:30am-11:00am" {
+		t.Errorf("wrong time change: %+v", f)
+	}
+	if f := changes[1].Fields[0]; f.Old != "Dec 13 3:00pm-6:00pm Wheeler 150" || f.New != "Dec 14 3:00pm-6:00pm Wheeler 150" {
+		t.Errorf("wrong exam change: %+v", f)
+	}
+}
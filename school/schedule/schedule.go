@@ -1,42 +1,31 @@
+// Package schedule registers the built in school
+// providers and fetches schedules from them.
 package schedule
 
 import (
-	"errors"
+	"context"
 
 	"github.com/harrybrwn/edu/school"
-	"github.com/harrybrwn/edu/school/ucberkeley/btime"
-	"github.com/harrybrwn/edu/school/ucmerced/ucm"
+
+	// register the built in providers
+	_ "github.com/harrybrwn/edu/school/ucberkeley/btime"
+	_ "github.com/harrybrwn/edu/school/ucmerced/ucm"
 )
 
 // Config is a set of config variables for
 // finding a school schedule.
-type Config struct {
-	Term string
-	Year int
-	// FilterClosed, if true, will filter out any courses
-	// that do not have seats open
-	FilterClosed bool
-	CourseName   string
-}
+type Config = school.Config
 
 // New will get a schedule based on the school type given.
 func New(sc school.School, config *Config) (school.Schedule, error) {
-	switch sc {
-	case school.UCBerkeley:
-		catalog, err := btime.New()
-		if err != nil {
-			return nil, err
-		}
-		return catalog, nil
-	case school.UCMerced:
-		sched, err := ucm.BySubject(
-			config.Year,
-			config.Term,
-			config.CourseName,
-			config.FilterClosed,
-		)
-		return &sched, err
-	default:
-		return nil, errors.New("unknown school")
+	return Get(context.Background(), sc.String(), config)
+}
+
+// Get will get a schedule from the provider registered as name.
+func Get(ctx context.Context, name string, config *Config) (school.Schedule, error) {
+	p, err := school.Lookup(name)
+	if err != nil {
+		return nil, err
 	}
+	return p.New(ctx, config)
 }
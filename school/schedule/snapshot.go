@@ -0,0 +1,219 @@
+package schedule
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+// Snapshot is a copy of a schedule that can be saved and
+// read back without the school that it came from.
+type Snapshot struct {
+	Fetched time.Time       `json:"fetched"`
+	Groups  []SnapshotGroup `json:"groups"`
+
+	courses map[int]*Course
+	stale   bool
+}
+
+// SnapshotGroup is a course and the sections linked to it.
+type SnapshotGroup struct {
+	Course   *Course   `json:"course"`
+	Sections []*Course `json:"sections,omitempty"`
+}
+
+// NewSnapshot copies a schedule. Sections are grouped the same
+// way as the schedule if it is a school.Grouper.
+func NewSnapshot(sched school.Schedule) *Snapshot {
+	snap := &Snapshot{Fetched: time.Now()}
+	if g, ok := sched.(school.Grouper); ok {
+		for _, grp := range g.SectionGroups() {
+			sg := SnapshotGroup{Course: copyCourse(grp.Course)}
+			for _, sec := range grp.Sections {
+				sg.Sections = append(sg.Sections, copyCourse(sec))
+			}
+			snap.Groups = append(snap.Groups, sg)
+		}
+	} else {
+		for _, c := range sched.Courses() {
+			snap.Groups = append(snap.Groups, SnapshotGroup{Course: copyCourse(c)})
+		}
+	}
+	snap.index()
+	return snap
+}
+
+// ReadSnapshot will read a snapshot from a file.
+func ReadSnapshot(file string) (*Snapshot, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	snap := &Snapshot{}
+	if err = json.Unmarshal(b, snap); err != nil {
+		return nil, err
+	}
+	snap.index()
+	return snap, nil
+}
+
+// WriteFile will save the snapshot to a file and create
+// the file's directory if it does not exist.
+func (s *Snapshot) WriteFile(file string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0775); err != nil {
+		return err
+	}
+	// write then rename so that readers never see half a file
+	tmp := file + ".tmp"
+	if err = ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+func (s *Snapshot) index() {
+	s.courses = make(map[int]*Course)
+	for _, g := range s.Groups {
+		s.courses[g.Course.CourseID] = g.Course
+		for _, sec := range g.Sections {
+			s.courses[sec.CourseID] = sec
+		}
+	}
+}
+
+// Age returns how long ago the schedule was fetched.
+func (s *Snapshot) Age() time.Duration {
+	return time.Since(s.Fetched)
+}
+
+// Stale returns true if the snapshot was used
+// because a new schedule could not be fetched.
+func (s *Snapshot) Stale() bool {
+	return s.stale
+}
+
+// Get returns the course with the given id.
+func (s *Snapshot) Get(id int) school.Course {
+	c, ok := s.courses[id]
+	if !ok {
+		return nil
+	}
+	return c
+}
+
+// Len returns the number of courses.
+func (s *Snapshot) Len() int {
+	return len(s.courses)
+}
+
+// Courses returns every course in group order.
+func (s *Snapshot) Courses() []school.Course {
+	list := make([]school.Course, 0, len(s.courses))
+	for _, g := range s.Groups {
+		list = append(list, g.Course)
+		for _, sec := range g.Sections {
+			list = append(list, sec)
+		}
+	}
+	return list
+}
+
+// SectionGroups returns the groups of the original schedule.
+func (s *Snapshot) SectionGroups() []school.Group {
+	groups := make([]school.Group, len(s.Groups))
+	for i, g := range s.Groups {
+		groups[i].Course = g.Course
+		for _, sec := range g.Sections {
+			groups[i].Sections = append(groups[i].Sections, sec)
+		}
+	}
+	return groups
+}
+
+// Course is a copy of a school.Course.
+type Course struct {
+	CourseID int               `json:"id"`
+	Title    string            `json:"title"`
+	Subject  string            `json:"subject"`
+	Number   string            `json:"number"`
+	Type     string            `json:"type,omitempty"`
+	Seq      string            `json:"section,omitempty"`
+	Teachers []string          `json:"instructors,omitempty"`
+	Units    float64           `json:"units"`
+	Seats    int               `json:"seats"`
+	Enrolled school.Enrollment `json:"enrollment"`
+	Meetings []school.Meeting  `json:"meetings,omitempty"`
+	Exam     *school.Meeting   `json:"exam,omitempty"`
+}
+
+func copyCourse(c school.Course) *Course {
+	subj, num := c.Code()
+	cp := &Course{
+		CourseID: c.ID(),
+		Title:    c.Name(),
+		Subject:  subj,
+		Number:   num,
+		Type:     c.SectionType(),
+		Teachers: c.Instructors(),
+		Units:    c.Credits(),
+		Seats:    c.SeatsOpen(),
+		Enrolled: c.Enrollment(),
+		Meetings: c.MeetingTimes(),
+	}
+	if seq, ok := c.(school.Sequencer); ok {
+		cp.Seq = seq.Sequence()
+	}
+	if ex, ok := c.(school.Examiner); ok {
+		cp.Exam = ex.FinalExam()
+	}
+	return cp
+}
+
+// ID returns the course's id.
+func (c *Course) ID() int { return c.CourseID }
+
+// Name returns the course's title.
+func (c *Course) Name() string { return c.Title }
+
+// SeatsOpen returns the number of open seats.
+func (c *Course) SeatsOpen() int { return c.Seats }
+
+// Code returns the subject and course number.
+func (c *Course) Code() (subject, number string) { return c.Subject, c.Number }
+
+// SectionType returns the type of section.
+func (c *Course) SectionType() string { return c.Type }
+
+// Sequence returns the section number.
+func (c *Course) Sequence() string { return c.Seq }
+
+// Instructors returns the course's instructors.
+func (c *Course) Instructors() []string { return c.Teachers }
+
+// Credits returns the course's units.
+func (c *Course) Credits() float64 { return c.Units }
+
+// Enrollment returns the course's enrollment numbers.
+func (c *Course) Enrollment() school.Enrollment { return c.Enrolled }
+
+// MeetingTimes returns the course's meeting times.
+func (c *Course) MeetingTimes() []school.Meeting { return c.Meetings }
+
+// FinalExam returns the course's final exam, it may be nil.
+func (c *Course) FinalExam() *school.Meeting { return c.Exam }
+
+var (
+	_ school.Schedule  = (*Snapshot)(nil)
+	_ school.Grouper   = (*Snapshot)(nil)
+	_ school.Course    = (*Course)(nil)
+	_ school.Sequencer = (*Course)(nil)
+	_ school.Examiner  = (*Course)(nil)
+)
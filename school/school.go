@@ -1,6 +1,9 @@
 package school
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 //go:generate stringer -type School -linecomment school.go
 
@@ -20,6 +23,41 @@ type Course interface {
 	SeatsOpen() int
 	Name() string
 	ID() int
+
+	// Code returns the subject code and the course
+	// number, i.e. "CSE" and "100".
+	Code() (subject, number string)
+	// SectionType is the type of section, i.e. LECT, LAB, DISC.
+	SectionType() string
+	Instructors() []string
+	Credits() float64
+	Enrollment() Enrollment
+	MeetingTimes() []Meeting
+}
+
+// Enrollment holds the enrollment numbers for a course.
+type Enrollment struct {
+	Capacity   int
+	Enrolled   int
+	Waitlisted int
+}
+
+// Meeting is a time and place that a course meets.
+type Meeting struct {
+	Days []time.Weekday
+	// Start and End are the time of day, they
+	// are zero if the time has not been decided.
+	Start, End time.Time
+	Location   string
+	Instructor string
+	// StartDate and EndDate are the first
+	// and last days of the meetings.
+	StartDate, EndDate time.Time
+}
+
+// TBD returns true if the meeting time has not been decided.
+func (m *Meeting) TBD() bool {
+	return m.Start.IsZero() && m.End.IsZero()
 }
 
 // Schedule is an interface that represents a
@@ -30,6 +68,26 @@ type Schedule interface {
 	Len() int
 }
 
+// Group is a course and the sections that must
+// be registered along with it.
+type Group struct {
+	Course   Course
+	Sections []Course
+}
+
+// Grouper is a schedule that knows which labs and
+// discussions belong to which lecture.
+type Grouper interface {
+	SectionGroups() []Group
+}
+
+// Examiner is a course that has a final exam.
+type Examiner interface {
+	// FinalExam returns the time and place of the final
+	// exam, it is nil if the course does not have one.
+	FinalExam() *Meeting
+}
+
 // FromName returns a school code based on the name of
 // the school. Returns -1 if the name is unknown.
 func FromName(schoolname string) School {
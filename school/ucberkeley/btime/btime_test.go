@@ -1,46 +1,400 @@
 package btime
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
 )
 
-var testingCatalog *Catalog
+func TestMain(m *testing.M) {
+	srv := newTestServer()
+	if err := SetBaseURL(srv.URL + "/api"); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
+
+// newTestServer creates a server that serves the recorded
+// api responses in the testdata directory.
+func newTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/catalog/catalog_json/filters/", func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(w, r, "catalog.json")
+	})
+	mux.HandleFunc("/api/catalog/filter/", func(w http.ResponseWriter, r *http.Request) {
+		filters := r.URL.Query()["filters"]
+		serveFixture(w, r, fmt.Sprintf("filter-%s.json", strings.Join(filters, "-")))
+	})
+	mux.HandleFunc("/api/catalog/catalog_json/course_box/", func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(w, r, fmt.Sprintf("course_box-%s.json", r.URL.Query().Get("course_id")))
+	})
+	// grades and enrollment fixtures are named after their path
+	apiFixture := func(w http.ResponseWriter, r *http.Request) {
+		name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+		serveFixture(w, r, strings.Replace(name, "/", "-", -1)+".json")
+	}
+	mux.HandleFunc("/api/grades/", apiFixture)
+	mux.HandleFunc("/api/enrollment/", apiFixture)
+	return httptest.NewServer(mux)
+}
 
-func testCatalog() Catalog {
-	var err error
-	if testingCatalog == nil {
-		testingCatalog, err = New()
+// serveFixture will serve the first fixture that exists
+// or respond with a 404.
+func serveFixture(w http.ResponseWriter, r *http.Request, names ...string) {
+	for _, name := range names {
+		b, err := ioutil.ReadFile(filepath.Join("testdata", name))
 		if err != nil {
-			panic(err)
+			continue
 		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+		return
 	}
-	return *testingCatalog
+	http.NotFound(w, r)
+}
+
+func testCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
 }
 
 func Test(t *testing.T) {
-	schedule := testCatalog()
+	schedule := testCatalog(t)
 	all := schedule.AllItems()
 	if len(all) < 1 {
 		t.Error("should not be empty")
 	}
 	res, err := schedule.DefaultFilter()
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	if len(res) == 0 {
+		t.Fatal("default filter should have results")
 	}
 	r := res[0]
-	course, err := r.Course()
+	course, err := r.Course(context.Background())
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 	if course == nil {
-		t.Error("got nil course")
+		t.Fatal("got nil course")
+	}
+	if len(course.Sections) != 3 || course.Sections[0].Ccn != "26201" {
+		t.Errorf("wrong sections: %+v", course.Sections)
+	}
+}
+
+func TestCatalogSchedule(t *testing.T) {
+	var cat school.Schedule = testCatalog(t)
+	if cat.Len() != 4 {
+		t.Fatalf("expected 4 courses from the default filter, got %d", cat.Len())
+	}
+	courses := cat.Courses()
+	seen := make(map[int]bool)
+	for _, c := range courses {
+		if seen[c.ID()] {
+			t.Errorf("course %d returned twice", c.ID())
+		}
+		seen[c.ID()] = true
+		if cat.Get(c.ID()) != c {
+			t.Errorf("Get(%d) should return the same course", c.ID())
+		}
+	}
+	if cat.Get(1) != nil {
+		t.Error("unknown id should be nil")
+	}
+	c := cat.Get(3399)
+	if c == nil {
+		t.Fatal("could not get course 3399")
+	}
+	if c.Credits() != 4 {
+		t.Errorf("units range should use the upper bound, got %v", c.Credits())
+	}
+	subj, num := cat.Get(2321).Code()
+	if subj != "COMPSCI" || num != "61A" {
+		t.Errorf("wrong code: %s %s", subj, num)
+	}
+	if e := cat.Get(2321).Enrollment(); e.Capacity != 1200 || e.Waitlisted != 5 {
+		t.Errorf("wrong enrollment: %+v", e)
+	}
+}
+
+func TestCatalogSelect(t *testing.T) {
+	cat := testCatalog(t)
+	if err := cat.Select("fall", 2030, ""); err == nil {
+		t.Error("expected an error for a semester that is not in the catalog")
+	}
+
+	// department filter item
+	if err := cat.Select("spring", 2021, "computer science"); err != nil {
+		t.Fatal(err)
+	}
+	if cat.Len() != 2 {
+		t.Errorf("expected 2 computer science courses, got %d", cat.Len())
+	}
+
+	// department abbreviation
+	if err := cat.Select("Spring", 2021, "math"); err != nil {
+		t.Fatal(err)
+	}
+	if cat.Len() != 2 || cat.Get(3301) == nil {
+		t.Errorf("expected the math courses, got %d", cat.Len())
+	}
+	if err := cat.Select("spring", 2021, "cs"); err != nil {
+		t.Fatal(err)
+	}
+	if cat.Get(2321) == nil || cat.Get(3301) != nil {
+		t.Error("cs should match the COMPSCI abbreviation")
+	}
+
+	cat.OpenOnly(true)
+	if cat.Len() != 1 || cat.Get(2322) != nil {
+		t.Errorf("closed courses should be left out, got %d courses", cat.Len())
+	}
+}
+
+func TestCatalogLoadError(t *testing.T) {
+	cat := testCatalog(t)
+	cat.filters = []string{"0"} // no fixture for this filter
+	if cat.Len() != 0 || cat.Courses() != nil {
+		t.Error("a catalog that failed to load should be empty")
+	}
+	if cat.Err() == nil {
+		t.Fatal("expected the load error to be kept")
+	}
+	if err := cat.Load(context.Background()); err == nil {
+		t.Error("expected an error from Load")
+	}
+	if err := cat.Select("spring", 2021, "computer science"); err != nil {
+		t.Fatal(err)
+	}
+	if cat.Err() != nil {
+		t.Error("selecting new filters should clear the load error")
+	}
+	if cat.Len() != 2 || cat.Err() != nil {
+		t.Errorf("expected the new selection to load, got %d courses: %v", cat.Len(), cat.Err())
+	}
+}
+
+func TestProvider(t *testing.T) {
+	p, err := school.Lookup("berkeley")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sched, err := p.New(context.Background(), &school.Config{Term: "spring", Year: 2021, CourseName: "CS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sched.Len() != 5 {
+		t.Errorf("expected 5 sections, got %d", sched.Len())
+	}
+	sched, err = p.New(context.Background(), &school.Config{Term: "spring", Year: 2021, CourseName: "CS", FilterClosed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sched.Len() != 2 || sched.Get(26201) == nil || sched.Get(26203) == nil {
+		t.Errorf("expected only the open sections, got %d", sched.Len())
+	}
+	if _, err = p.New(context.Background(), &school.Config{Term: "summer", Year: 2021, CourseName: "CS"}); err == nil {
+		t.Error("expected an error for a missing semester")
+	}
+	if _, err = p.New(context.Background(), &school.Config{Term: "spring", Year: 2021}); err != ErrNoDepartment {
+		t.Errorf("expected an error when there is no department, got %v", err)
+	}
+}
+
+func TestSections(t *testing.T) {
+	cat := testCatalog(t)
+	if err := cat.Select("spring", 2021, "computer science"); err != nil {
+		t.Fatal(err)
+	}
+	sched, err := cat.Sections(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	courses := sched.Courses()
+	ccns := []int{26201, 26202, 26203, 26301, 26302}
+	if len(courses) != len(ccns) {
+		t.Fatalf("expected %d sections, got %d", len(ccns), len(courses))
+	}
+	for i, c := range courses {
+		if c.ID() != ccns[i] {
+			t.Errorf("section %d: expected %d, got %d", i, ccns[i], c.ID())
+		}
+	}
+
+	groups := sched.SectionGroups()
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].Course.ID() != 26201 || len(groups[0].Sections) != 2 {
+		t.Errorf("wrong group for 61A: %d with %d sections", groups[0].Course.ID(), len(groups[0].Sections))
+	}
+
+	disc := sched.Get(26202)
+	if disc == nil {
+		t.Fatal("could not find 26202")
+	}
+	if disc.SeatsOpen() != 0 {
+		t.Errorf("expected no seats, got %d", disc.SeatsOpen())
+	}
+	over := &Section{EnrolledMax: 30, Enrolled: 32}
+	if over.SeatsOpen() != 0 {
+		t.Errorf("over enrolled sections should have no seats, got %d", over.SeatsOpen())
+	}
+	if e := disc.Enrollment(); e.Waitlisted != 2 || e.Capacity != 30 {
+		t.Errorf("wrong enrollment: %+v", e)
+	}
+	subj, num := disc.Code()
+	if subj != "COMPSCI" || num != "61A" || disc.Name() != "The Structure and Interpretation of Computer Programs" {
+		t.Errorf("wrong course for section: %s %s %q", subj, num, disc.Name())
+	}
+	m := disc.MeetingTimes()[0]
+	if len(m.Days) != 2 || m.Days[0] != time.Tuesday || m.Days[1] != time.Thursday {
+		t.Errorf("wrong days: %v", m.Days)
+	}
+	if m.Start.Hour() != 14 || m.End.Minute() != 30 {
+		t.Errorf("wrong time: %v-%v", m.Start, m.End)
+	}
+	if tbd := sched.Get(26203).MeetingTimes()[0]; !tbd.TBD() || len(tbd.Days) != 0 {
+		t.Errorf("section with no time should be TBD: %+v", tbd)
+	}
+}
+
+func TestParseWordDays(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		days []time.Weekday
+	}{
+		{"MWF", []time.Weekday{time.Monday, time.Wednesday, time.Friday}},
+		{"TuTh", []time.Weekday{time.Tuesday, time.Thursday}},
+		{"MTuWThF", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{"SaSu", []time.Weekday{time.Saturday, time.Sunday}},
+		{"", []time.Weekday{}},
+	} {
+		days := parseWordDays(tt.in)
+		if fmt.Sprint(days) != fmt.Sprint(tt.days) {
+			t.Errorf("parseWordDays(%q): expected %v, got %v", tt.in, tt.days, days)
+		}
+	}
+}
+
+func TestGrades(t *testing.T) {
+	sections, err := CourseGrades(2321)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 3 {
+		t.Fatalf("expected 3 grade sections, got %d", len(sections))
+	}
+	byInstructor := sections.ByInstructor()
+	if len(byInstructor) != 2 || byInstructor[0].Name != "DeNero, John" {
+		t.Fatalf("wrong instructor groups: %+v", byInstructor)
+	}
+	if fmt.Sprint(byInstructor[0].IDs) != "[501 503]" {
+		t.Errorf("wrong ids: %v", byInstructor[0].IDs)
+	}
+	bySemester := sections.BySemester()
+	if len(bySemester) != 2 || bySemester[1].Name != "fall 2020" || len(bySemester[1].IDs) != 2 {
+		t.Errorf("wrong semester groups: %+v", bySemester)
+	}
+
+	// ids should be sorted before the request
+	d, err := GradeDistribution(503, 501)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Denominator != 200 || d.SectionLetter != "A-" || d.CourseGPA != 3.25 {
+		t.Errorf("wrong distribution: %+v", d)
+	}
+	if d.Grades["A"].Numerator != 60 || d.Grades["P"].Percent != 0.08 {
+		t.Errorf("wrong grades: %+v", d.Grades)
+	}
+	letters := d.Letters()
+	if len(letters) != 8 || letters[0] != "A+" || letters[len(letters)-1] != "P" {
+		t.Errorf("wrong letters: %v", letters)
+	}
+	if _, err = GradeDistribution(); err == nil {
+		t.Error("expected an error with no grade ids")
 	}
 }
 
-func TestSchedual(t *testing.T) {
-	cat := testCatalog()
-	for _, u := range cat.Semester {
-		fmt.Println(u)
+func TestEnrollment(t *testing.T) {
+	semesters, err := EnrollmentSemesters(2321)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(semesters) != 2 || semesters[0].Semester != "spring" || len(semesters[0].Sections) != 2 {
+		t.Errorf("wrong semesters: %+v", semesters)
+	}
+	et, err := Enrollment(2321, "Spring", 2021)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(et.Data) != 4 || et.EnrolledMax != 1200 {
+		t.Fatalf("wrong enrollment trend: %+v", et)
+	}
+	if fmt.Sprint(et.Enrolled()) != "[300 700 1100 1200]" {
+		t.Errorf("wrong enrolled counts: %v", et.Enrolled())
+	}
+	if full := et.Filled(); full == nil || full.Day != 3 {
+		t.Errorf("course should fill on day 3, got %+v", full)
+	}
+	if _, err = Enrollment(2321, "fall", 2030); err == nil {
+		t.Error("expected an error for a missing semester")
+	}
+}
+
+func TestFindCourse(t *testing.T) {
+	r, err := FindCourse("cs", "61a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ResultID != 2321 {
+		t.Errorf("wrong course: %d", r.ResultID)
+	}
+	if _, err = FindCourse("cs", "1000"); err == nil {
+		t.Error("expected an error for an unknown course")
+	}
+}
+
+func TestClient(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.UserAgent() != "edu-test" {
+			t.Errorf("wrong user agent: %q", r.UserAgent())
+		}
+		<-block
+	}))
+	defer srv.Close()
+	defer close(block)
+
+	c := NewClient()
+	if err := c.SetBaseURL(srv.URL + "/api"); err != nil {
+		t.Fatal(err)
+	}
+	c.UserAgent = "edu-test"
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Catalog(ctx); err == nil {
+		t.Error("expected the request to time out")
+	}
+	if c.BaseURL() == defaultClient.BaseURL() {
+		t.Error("the client should not share the default base url")
 	}
 }
@@ -2,32 +2,28 @@
 package btime
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/harrybrwn/edu/school"
 )
 
-const (
-	catalogURL = "https://www.berkeleytime.com/api/catalog/catalog_json/filters/"
-	filterURL  = ""
-)
-
 // New creates a new catalog
 func New() (*Catalog, error) {
-	c := &Catalog{}
-	resp, err := http.Get(catalogURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if err = json.NewDecoder(resp.Body).Decode(c); err != nil {
+	return defaultClient.Catalog(context.Background())
+}
+
+// Catalog gets the catalog's filter items.
+func (c *Client) Catalog(ctx context.Context) (*Catalog, error) {
+	cat := &Catalog{client: c}
+	req := c.newRequest(ctx, "/catalog/catalog_json/filters/", nil)
+	if err := c.getJSON(req, cat); err != nil {
 		return nil, err
 	}
-	return c, nil
+	return cat, nil
 }
 
 // Catalog is a json struct for a catalog
@@ -50,7 +46,18 @@ type Catalog struct {
 	DefaultPlaylists string `json:"default_playlists"`
 	DefaultCourse    string `json:"default_course"`
 
+	client *Client
+	// filters are the filter item ids used to get results
+	filters []string
+	// department is a department abbreviation used to filter
+	// results when no department filter item matches
+	department string
+	openOnly   bool
+
+	results []*Result
 	courses map[int]*Result
+	// err is the error from the last call to Load
+	err error
 }
 
 // AllItems returns a slice of all of the items in the catalog.
@@ -75,9 +82,106 @@ func (c *Catalog) AllItems() []Item {
 	return items
 }
 
+// Select will set the semester and department that the catalog's
+// courses are filtered by. The semester is found using the semester
+// filter items. The department is matched against the department
+// filter items by name and if none match, courses are filtered by
+// their abbreviation. An empty semester uses the default filter and
+// an empty department gets all departments.
+func (c *Catalog) Select(semester string, year int, department string) error {
+	var filters []string
+	if semester == "" {
+		filters = c.defaultFilters()
+	} else {
+		item := c.Semester.Find(semester, year)
+		if item == nil {
+			return fmt.Errorf("%s %d is not in the catalog", semester, year)
+		}
+		filters = append(filters, strconv.Itoa(item.ID))
+	}
+	c.department = ""
+	if department != "" && !strings.EqualFold(department, "all") {
+		if item := c.Department.Match(department); item != nil {
+			filters = append(filters, strconv.Itoa(item.ID))
+		} else {
+			c.department = department
+		}
+	}
+	c.filters = filters
+	c.results, c.courses, c.err = nil, nil, nil
+	return nil
+}
+
+// OpenOnly will make the catalog leave out
+// courses that have no open seats.
+func (c *Catalog) OpenOnly(open bool) {
+	c.openOnly = open
+	c.results, c.courses, c.err = nil, nil, nil
+}
+
+// Load will get the courses for the selected filters. Load
+// is called by Courses, Get, and Len if it has not been called.
+func (c *Catalog) Load(ctx context.Context) error {
+	filters := c.filters
+	if filters == nil {
+		filters = c.defaultFilters()
+	}
+	results, err := c.getClient().sendFilter(ctx, filters)
+	c.err = err
+	if err != nil {
+		return err
+	}
+	c.results = make([]*Result, 0, len(results))
+	c.courses = make(map[int]*Result, len(results))
+	for i := range results {
+		r := &results[i]
+		r.client = c.getClient()
+		if c.department != "" && !sameDepartment(r.Abbreviation, c.department) {
+			continue
+		}
+		if c.openOnly && r.OpenSeats <= 0 {
+			continue
+		}
+		c.results = append(c.results, r)
+		c.courses[r.ResultID] = r
+	}
+	return nil
+}
+
+// Err returns the error from the last time the courses were
+// loaded. Courses, Get, and Len load the courses lazily and
+// will return empty results if there was an error.
+func (c *Catalog) Err() error {
+	return c.err
+}
+
+func (c *Catalog) load() bool {
+	if c.courses != nil {
+		return true
+	}
+	if c.err != nil {
+		return false
+	}
+	return c.Load(context.Background()) == nil
+}
+
+func (c *Catalog) getClient() *Client {
+	if c.client == nil {
+		return defaultClient
+	}
+	return c.client
+}
+
+func (c *Catalog) defaultFilters() []string {
+	if c.DefaultPlaylists == "" {
+		return []string{}
+	}
+	return strings.Split(c.DefaultPlaylists, ",")
+}
+
 // Get will get a course given an id.
 func (c *Catalog) Get(id int) school.Course {
-	if c.courses == nil {
+	if !c.load() {
 		return nil
 	}
 	course, ok := c.courses[id]
@@ -87,9 +191,28 @@ func (c *Catalog) Get(id int) school.Course {
 	return course
 }
 
-// Len gets the length of the catalog
+// Len gets the number of courses in the catalog.
 func (c *Catalog) Len() int {
-	panic("TODO: find out how to get catalog length")
+	if !c.load() {
+		return 0
+	}
+	return len(c.results)
+}
+
+// departmentAliases are common short names for
+// departments that are abbreviated differently.
+var departmentAliases = map[string]string{
+	"CS": "COMPSCI",
+	"EE": "EL ENG",
+	"ME": "MEC ENG",
+}
+
+func sameDepartment(abbreviation, dept string) bool {
+	dept = strings.ToUpper(dept)
+	if alias, ok := departmentAliases[dept]; ok {
+		dept = alias
+	}
+	return strings.EqualFold(abbreviation, dept)
 }
 
 // Items is a slice of Item structs
@@ -98,9 +221,32 @@ type Items []Item
 // Search the list of items given a search term.
 func (its Items) Search(term string) *Item {
 	term = strings.ToLower(term)
-	for _, itm := range its {
-		if strings.Contains(strings.ToLower(itm.Name), term) {
-			return &itm
+	for i := range its {
+		if strings.Contains(strings.ToLower(its[i].Name), term) {
+			return &its[i]
+		}
+	}
+	return nil
+}
+
+// Find will find the semester item for a
+// semester name and year.
+func (its Items) Find(semester string, year int) *Item {
+	y := strconv.Itoa(year)
+	for i := range its {
+		if strings.EqualFold(its[i].Semester, semester) && its[i].Year == y {
+			return &its[i]
+		}
+	}
+	return nil
+}
+
+// Match will find the item whose name matches
+// name exactly, ignoring case.
+func (its Items) Match(name string) *Item {
+	for i := range its {
+		if strings.EqualFold(its[i].Name, name) {
+			return &its[i]
 		}
 	}
 	return nil
@@ -134,6 +280,8 @@ type Result struct {
 
 	GradeAverage  float64 `json:"grade_average"`
 	LetterAverage string  `json:"letter_average"`
+
+	client *Client
 }
 
 // ID returns the id
@@ -147,26 +295,24 @@ func (r *Result) Name() string {
 }
 
 // Course will get the course associated with the filter result.
-func (r *Result) Course() (*Course, error) {
-	req := &http.Request{
-		Method: "GET",
-		Proto:  "HTTP/1.1",
-		URL: &url.URL{
-			Scheme:   "https",
-			Host:     "www.berkeleytime.com",
-			Path:     "/api/catalog/catalog_json/course_box/",
-			RawQuery: fmt.Sprintf("course_id=%d", r.ResultID),
-		},
-	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+func (r *Result) Course(ctx context.Context) (*Course, error) {
+	c := r.client
+	if c == nil {
+		c = defaultClient
 	}
-	defer resp.Body.Close()
+	return c.Course(ctx, r.ResultID)
+}
+
+// Course will get a course and its sections given the course id.
+func (c *Client) Course(ctx context.Context, id int) (*Course, error) {
+	req := c.newRequest(ctx, "/catalog/catalog_json/course_box/", url.Values{
+		"course_id": {strconv.Itoa(id)},
+	})
 	course := &Course{}
-	if err = json.NewDecoder(resp.Body).Decode(course); err != nil {
+	if err := c.getJSON(req, course); err != nil {
 		return nil, err
 	}
+	course.link()
 	return course, nil
 }
 
@@ -176,24 +322,72 @@ func (r *Result) SeatsOpen() int {
 	return r.OpenSeats
 }
 
+// Code returns the department abbreviation and course number.
+func (r *Result) Code() (subject, number string) {
+	return r.Abbreviation, r.CourseNumber
+}
+
+// SectionType returns an empty string, filter results
+// are courses and not sections.
+func (r *Result) SectionType() string {
+	return ""
+}
+
+// Instructors returns nil, filter results do
+// not have instructors.
+func (r *Result) Instructors() []string {
+	return nil
+}
+
+// Credits returns the number of units. For a range
+// of units like "1-4", the upper bound is returned.
+func (r *Result) Credits() float64 {
+	return parseUnits(r.Units)
+}
+
+func parseUnits(s string) float64 {
+	units := strings.Split(s, "-")
+	n, err := strconv.ParseFloat(strings.TrimSpace(units[len(units)-1]), 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Enrollment returns the enrollment numbers, the capacity
+// is derived from the enrolled percentage.
+func (r *Result) Enrollment() school.Enrollment {
+	e := school.Enrollment{
+		Enrolled:   r.Enrolled,
+		Waitlisted: r.Waitlisted,
+	}
+	if r.EnrolledPercentage > 0 {
+		e.Capacity = int(float64(r.Enrolled)/r.EnrolledPercentage + 0.5)
+	}
+	return e
+}
+
+// MeetingTimes returns nil, filter results do
+// not have meeting times.
+func (r *Result) MeetingTimes() []school.Meeting {
+	return nil
+}
+
 // DefaultFilter makes a filter request to the catalog's default
 // filter parameters
 func (c *Catalog) DefaultFilter() (Results, error) {
-	opts := strings.Split(c.DefaultPlaylists, ",")
-	return sendFilter(opts)
+	return c.getClient().sendFilter(context.Background(), c.defaultFilters())
 }
 
 // Courses returns a slice of Results in a generic
 // Course interface format. Returns nil on error.
 func (c *Catalog) Courses() []school.Course {
-	results, err := c.DefaultFilter()
-	if err != nil {
+	if !c.load() {
 		return nil
 	}
-	courses := make([]school.Course, len(results))
-	for i, r := range results {
-		c.courses[r.ResultID] = &r
-		courses[i] = &r
+	courses := make([]school.Course, len(c.results))
+	for i, r := range c.results {
+		courses[i] = r
 	}
 	return courses
 }
@@ -201,34 +395,29 @@ func (c *Catalog) Courses() []school.Course {
 // Filter will return the results from a filter request given
 // filter option IDs.
 func Filter(opts ...interface{}) (Results, error) {
+	return defaultClient.Filter(context.Background(), opts...)
+}
+
+// Filter will return the results from a filter request given
+// filter option IDs.
+func (c *Client) Filter(ctx context.Context, opts ...interface{}) (Results, error) {
 	filter := make([]string, len(opts))
 	for i, o := range opts {
 		filter[i] = fmt.Sprintf("%v", o)
 	}
-	return sendFilter(filter)
+	return c.sendFilter(ctx, filter)
 }
 
-func sendFilter(filter []string) (Results, error) {
-	req := &http.Request{
-		Method: "GET",
-		Proto:  "HTTP/1.1",
-		URL: &url.URL{
-			Scheme: "https",
-			Host:   "www.berkeleytime.com",
-			Path:   "/api/catalog/filter/",
-			RawQuery: (&url.Values{
-				"filters": filter,
-			}).Encode(),
-		},
-		Header: make(http.Header),
+func (c *Client) sendFilter(ctx context.Context, filter []string) (Results, error) {
+	req := c.newRequest(ctx, "/catalog/filter/", url.Values{"filters": filter})
+	res := make([]Result, 0)
+	if err := c.getJSON(req, &res); err != nil {
+		return res, err
 	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
+	for i := range res {
+		res[i].client = c
 	}
-	defer resp.Body.Close()
-	res := make([]Result, 0)
-	return res, json.NewDecoder(resp.Body).Decode(&res)
+	return res, nil
 }
 
 var (
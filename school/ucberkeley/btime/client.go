@@ -0,0 +1,123 @@
+package btime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client is a berkeleytime api client. Every request is made
+// with a context so that slow requests can be canceled.
+type Client struct {
+	client *http.Client
+	base   url.URL
+
+	// UserAgent is sent with every request if not empty.
+	UserAgent string
+}
+
+var client = http.Client{
+	Timeout: time.Minute,
+}
+
+// defaultClient is used by all the package level
+// functions and it uses the package level http client.
+var defaultClient = ClientFromClient(&client)
+
+// NewClient creates a new berkeleytime client.
+func NewClient() *Client {
+	return ClientFromClient(&http.Client{Timeout: time.Minute})
+}
+
+// ClientFromClient creates a berkeleytime client
+// that uses a user given http.Client.
+func ClientFromClient(c *http.Client) *Client {
+	return &Client{
+		client: c,
+		base: url.URL{
+			Scheme: "https",
+			Host:   "www.berkeleytime.com",
+			Path:   "/api",
+		},
+	}
+}
+
+// SetHTTPClient lets callers set the package level http client.
+func SetHTTPClient(c http.Client) {
+	client = c
+}
+
+// SetTransport sets the transport used by the package level http client.
+func SetTransport(rt http.RoundTripper) {
+	client.Transport = rt
+}
+
+// SetBaseURL will change the url that api
+// requests are sent to.
+func SetBaseURL(u string) error {
+	return defaultClient.SetBaseURL(u)
+}
+
+// SetTransport sets the transport used by the client.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.client.Transport = rt
+}
+
+// SetTimeout sets the timeout for each request. Use a
+// context for a deadline that covers many requests.
+func (c *Client) SetTimeout(tm time.Duration) {
+	c.client.Timeout = tm
+}
+
+// SetBaseURL will change the url that the client's
+// requests are sent to, i.e. a mirror of the api.
+func (c *Client) SetBaseURL(u string) error {
+	base, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return fmt.Errorf("base url %q must have a scheme and host", u)
+	}
+	c.base = *base
+	return nil
+}
+
+// BaseURL returns the url that requests are sent to.
+func (c *Client) BaseURL() string {
+	return c.base.String()
+}
+
+// newRequest creates a GET request for a path relative to
+// the base url. The api needs the trailing slash on paths.
+func (c *Client) newRequest(ctx context.Context, p string, query url.Values) *http.Request {
+	u := c.base
+	u.Path = strings.TrimRight(c.base.Path, "/") + p
+	u.RawQuery = query.Encode()
+	req := &http.Request{
+		Method: "GET",
+		Proto:  "HTTP/1.1",
+		URL:    &u,
+		Header: make(http.Header),
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	return req.WithContext(ctx)
+}
+
+func (c *Client) getJSON(req *http.Request, v interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", req.URL.Path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
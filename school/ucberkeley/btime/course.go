@@ -20,43 +20,33 @@ type Course struct {
 	} `json:"course"`
 	Marketplace struct {
 	} `json:"marketplace"`
-	LastEnrollmentUpdate string   `json:"last_enrollment_update"`
-	Requirements         []string `json:"requirements"`
-	Favorited            bool     `json:"favorited"`
-	Ongoing              bool     `json:"ongoing"`
-	CoverPhoto           string   `json:"cover_photo"`
-	Sections             []struct {
-		Kind          string `json:"kind"`
-		LocationName  string `json:"location_name"`
-		Waitlisted    int    `json:"waitlisted"`
-		FinalEnd      string `json:"final_end"`
-		StartTime     string `json:"start_time"`
-		SectionNumber string `json:"section_number"`
-		FinalStart    string `json:"final_start"`
-		WordDays      string `json:"word_days"`
-		Ccn           string `json:"ccn"`
-		EnrolledMax   int    `json:"enrolled_max"`
-		EndTime       string `json:"end_time"`
-		FinalDay      string `json:"final_day"`
-		Enrolled      int    `json:"enrolled"`
-		Instructor    string `json:"instructor"`
-		ID            int    `json:"id"`
-	} `json:"sections"`
-	OngoingSections []struct {
-		Kind          string `json:"kind"`
-		LocationName  string `json:"location_name"`
-		Waitlisted    int    `json:"waitlisted"`
-		FinalEnd      string `json:"final_end"`
-		StartTime     string `json:"start_time"`
-		SectionNumber string `json:"section_number"`
-		FinalStart    string `json:"final_start"`
-		WordDays      string `json:"word_days"`
-		Ccn           string `json:"ccn"`
-		EnrolledMax   int    `json:"enrolled_max"`
-		EndTime       string `json:"end_time"`
-		FinalDay      string `json:"final_day"`
-		Enrolled      int    `json:"enrolled"`
-		Instructor    string `json:"instructor"`
-		ID            int    `json:"id"`
-	} `json:"ongoing_sections"`
+	LastEnrollmentUpdate string    `json:"last_enrollment_update"`
+	Requirements         []string  `json:"requirements"`
+	Favorited            bool      `json:"favorited"`
+	Ongoing              bool      `json:"ongoing"`
+	CoverPhoto           string    `json:"cover_photo"`
+	Sections             []Section `json:"sections"`
+	OngoingSections      []Section `json:"ongoing_sections"`
+}
+
+// Section is a lecture, lab, or discussion
+// section of a course.
+type Section struct {
+	Kind          string `json:"kind"`
+	LocationName  string `json:"location_name"`
+	Waitlisted    int    `json:"waitlisted"`
+	FinalEnd      string `json:"final_end"`
+	StartTime     string `json:"start_time"`
+	SectionNumber string `json:"section_number"`
+	FinalStart    string `json:"final_start"`
+	WordDays      string `json:"word_days"`
+	Ccn           string `json:"ccn"`
+	EnrolledMax   int    `json:"enrolled_max"`
+	EndTime       string `json:"end_time"`
+	FinalDay      string `json:"final_day"`
+	Enrolled      int    `json:"enrolled"`
+	Instructor    string `json:"instructor"`
+	SectionID     int    `json:"id"`
+
+	course *Course
 }
@@ -0,0 +1,123 @@
+package btime
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EnrollmentSemester is a semester that has
+// enrollment data for a course.
+type EnrollmentSemester struct {
+	Semester string `json:"semester"`
+	Year     string `json:"year"`
+	Sections []struct {
+		SectionNumber string `json:"section_number"`
+		SectionID     int    `json:"section_id"`
+	} `json:"sections"`
+}
+
+// EnrollmentSemesters gets the semesters that have
+// enrollment data for a course, newest first.
+func EnrollmentSemesters(courseID int) ([]EnrollmentSemester, error) {
+	return defaultClient.EnrollmentSemesters(context.Background(), courseID)
+}
+
+// EnrollmentSemesters gets the semesters that have
+// enrollment data for a course, newest first.
+func (c *Client) EnrollmentSemesters(ctx context.Context, courseID int) ([]EnrollmentSemester, error) {
+	req := c.newRequest(ctx, fmt.Sprintf("/enrollment/sections/%d/", courseID), nil)
+	semesters := make([]EnrollmentSemester, 0)
+	return semesters, c.getJSON(req, &semesters)
+}
+
+// EnrollmentPoint is the enrollment of a
+// course on one day of registration.
+type EnrollmentPoint struct {
+	Day               int     `json:"day"`
+	Date              string  `json:"date"`
+	Enrolled          int     `json:"enrolled"`
+	EnrolledMax       int     `json:"enrolled_max"`
+	EnrolledPercent   float64 `json:"enrolled_percent"`
+	Waitlisted        int     `json:"waitlisted"`
+	WaitlistedPercent float64 `json:"waitlisted_percent"`
+}
+
+// EnrollmentTrend is the enrollment of a course
+// over the registration period of a semester.
+type EnrollmentTrend struct {
+	CourseID      int               `json:"course_id"`
+	Title         string            `json:"title"`
+	Subtitle      string            `json:"subtitle"`
+	EnrolledMax   int               `json:"enrolled_max"`
+	WaitlistedMax int               `json:"waitlisted_max"`
+	Data          []EnrollmentPoint `json:"data"`
+}
+
+// Enrolled returns the number enrolled on each day.
+func (et *EnrollmentTrend) Enrolled() []int {
+	counts := make([]int, len(et.Data))
+	for i, p := range et.Data {
+		counts[i] = p.Enrolled
+	}
+	return counts
+}
+
+// Filled returns the first point where the course was full
+// or nil if it never filled.
+func (et *EnrollmentTrend) Filled() *EnrollmentPoint {
+	for i, p := range et.Data {
+		if p.EnrolledMax > 0 && p.Enrolled >= p.EnrolledMax {
+			return &et.Data[i]
+		}
+	}
+	return nil
+}
+
+// Enrollment gets the enrollment trend of all the
+// sections of a course for a semester.
+func Enrollment(courseID int, semester string, year int) (*EnrollmentTrend, error) {
+	return defaultClient.Enrollment(context.Background(), courseID, semester, year)
+}
+
+// Enrollment gets the enrollment trend of all the
+// sections of a course for a semester.
+func (c *Client) Enrollment(ctx context.Context, courseID int, semester string, year int) (*EnrollmentTrend, error) {
+	req := c.newRequest(ctx, fmt.Sprintf(
+		"/enrollment/aggregate/%d/%s/%s/",
+		courseID, strings.ToLower(semester), strconv.Itoa(year),
+	), nil)
+	et := &EnrollmentTrend{}
+	if err := c.getJSON(req, et); err != nil {
+		return nil, err
+	}
+	return et, nil
+}
+
+// FindCourse will search the catalog's default filter
+// for a course given its department and course number.
+func FindCourse(department, number string) (*Result, error) {
+	return defaultClient.FindCourse(context.Background(), department, number)
+}
+
+// FindCourse will search the catalog's default filter
+// for a course given its department and course number.
+func (c *Client) FindCourse(ctx context.Context, department, number string) (*Result, error) {
+	cat, err := c.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = cat.Select("", 0, department); err != nil {
+		return nil, err
+	}
+	if err = cat.Load(ctx); err != nil {
+		return nil, err
+	}
+	for _, r := range cat.results {
+		if strings.EqualFold(r.CourseNumber, number) {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find %s %s", department, number)
+}
@@ -0,0 +1,176 @@
+package btime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GradeLetters are the grades in a distribution
+// from highest to lowest.
+var GradeLetters = []string{
+	"A+", "A", "A-",
+	"B+", "B", "B-",
+	"C+", "C", "C-",
+	"D+", "D", "D-",
+	"F", "P", "NP",
+}
+
+// GradeSection is a past section of a course
+// that has a grade distribution.
+type GradeSection struct {
+	GradeID       int    `json:"grade_id"`
+	Instructor    string `json:"instructor"`
+	Semester      string `json:"semester"`
+	Year          string `json:"year"`
+	SectionNumber string `json:"section_number"`
+}
+
+// Term returns the semester and year, i.e. "spring 2021".
+func (gs *GradeSection) Term() string {
+	return gs.Semester + " " + gs.Year
+}
+
+// GradeSections is a list of grade sections.
+type GradeSections []GradeSection
+
+// CourseGrades gets the sections of a course that
+// have grade distributions.
+func CourseGrades(courseID int) (GradeSections, error) {
+	return defaultClient.CourseGrades(context.Background(), courseID)
+}
+
+// CourseGrades gets the sections of a course that
+// have grade distributions.
+func (c *Client) CourseGrades(ctx context.Context, courseID int) (GradeSections, error) {
+	req := c.newRequest(ctx, fmt.Sprintf("/grades/course_grades/%d/", courseID), nil)
+	sections := make(GradeSections, 0)
+	return sections, c.getJSON(req, &sections)
+}
+
+// GradeGroup is a named set of grade ids.
+type GradeGroup struct {
+	Name string
+	IDs  []int
+}
+
+// ByInstructor groups the grade sections by instructor.
+func (gs GradeSections) ByInstructor() []GradeGroup {
+	return gs.group(func(s *GradeSection) string { return s.Instructor })
+}
+
+// BySemester groups the grade sections by semester.
+func (gs GradeSections) BySemester() []GradeGroup {
+	return gs.group((*GradeSection).Term)
+}
+
+// group will keep the groups in the order
+// that they first appear.
+func (gs GradeSections) group(key func(*GradeSection) string) []GradeGroup {
+	var (
+		groups = make([]GradeGroup, 0)
+		index  = make(map[string]int)
+	)
+	for i := range gs {
+		k := key(&gs[i])
+		j, ok := index[k]
+		if !ok {
+			j = len(groups)
+			index[k] = j
+			groups = append(groups, GradeGroup{Name: k})
+		}
+		groups[j].IDs = append(groups[j].IDs, gs[i].GradeID)
+	}
+	return groups
+}
+
+// Grade is the number of students that got a grade.
+type Grade struct {
+	Numerator int     `json:"numerator"`
+	Percent   float64 `json:"percent"`
+}
+
+// Distribution is a grade distribution for a
+// set of sections.
+type Distribution struct {
+	CourseID      int     `json:"course_id"`
+	Title         string  `json:"title"`
+	Subtitle      string  `json:"subtitle"`
+	Subject       string  `json:"subject"`
+	CourseGPA     float64 `json:"course_gpa_average"`
+	CourseLetter  string  `json:"course_letter"`
+	SectionGPA    float64 `json:"section_gpa"`
+	SectionLetter string  `json:"section_letter"`
+	Denominator   int     `json:"denominator"`
+
+	// Grades maps grade letters to their counts.
+	Grades map[string]Grade `json:"-"`
+}
+
+// UnmarshalJSON will decode a distribution where
+// the grades are keys in the top level object.
+func (d *Distribution) UnmarshalJSON(b []byte) error {
+	type distribution Distribution
+	if err := json.Unmarshal(b, (*distribution)(d)); err != nil {
+		return err
+	}
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	d.Grades = make(map[string]Grade)
+	for _, letter := range GradeLetters {
+		msg, ok := raw[letter]
+		if !ok {
+			continue
+		}
+		var g Grade
+		if err := json.Unmarshal(msg, &g); err != nil {
+			return fmt.Errorf("grade %s: %w", letter, err)
+		}
+		d.Grades[letter] = g
+	}
+	return nil
+}
+
+// Letters returns the grades in the distribution
+// from highest to lowest.
+func (d *Distribution) Letters() []string {
+	letters := make([]string, 0, len(d.Grades))
+	for _, l := range GradeLetters {
+		if _, ok := d.Grades[l]; ok {
+			letters = append(letters, l)
+		}
+	}
+	return letters
+}
+
+// GradeDistribution gets the combined grade distribution
+// of a set of grade ids.
+func GradeDistribution(gradeIDs ...int) (*Distribution, error) {
+	return defaultClient.GradeDistribution(context.Background(), gradeIDs...)
+}
+
+// GradeDistribution gets the combined grade distribution
+// of a set of grade ids.
+func (c *Client) GradeDistribution(ctx context.Context, gradeIDs ...int) (*Distribution, error) {
+	if len(gradeIDs) == 0 {
+		return nil, fmt.Errorf("no grade ids")
+	}
+	ids := make([]int, len(gradeIDs))
+	copy(ids, gradeIDs)
+	sort.Ints(ids)
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = strconv.Itoa(id)
+	}
+	req := c.newRequest(ctx, fmt.Sprintf("/grades/sections/%s/", strings.Join(strs, "&")), nil)
+	d := &Distribution{}
+	if err := c.getJSON(req, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,59 @@
+package btime
+
+import (
+	"context"
+	"errors"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+// Provider is the UC Berkeley school provider backed by
+// berkeleytime. Its schedules are made of sections keyed
+// by CCN.
+var Provider = &school.Provider{
+	Name:    "ucberkeley",
+	Aliases: []string{"berkeley", "btime"},
+	Title:   "UC Berkeley",
+	BaseURL: "https://www.berkeleytime.com",
+	Terms: map[string]string{
+		"spring": "spring",
+		"summer": "summer",
+		"fall":   "fall",
+	},
+	Capabilities: school.Seats |
+		school.Meetings |
+		school.SectionLinks,
+	TimeZone: "America/Los_Angeles",
+	New: func(ctx context.Context, conf *school.Config) (school.Schedule, error) {
+		if conf.CourseName == "" {
+			return nil, ErrNoDepartment
+		}
+		catalog, err := defaultClient.Catalog(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err = catalog.Select(conf.Term, conf.Year, conf.CourseName); err != nil {
+			return nil, err
+		}
+		if err = catalog.Load(ctx); err != nil {
+			return nil, err
+		}
+		sched, err := catalog.Sections(ctx, 4)
+		if err != nil {
+			return nil, err
+		}
+		if conf.FilterClosed {
+			sched.RemoveClosed()
+		}
+		return sched, nil
+	},
+}
+
+// ErrNoDepartment is returned when getting a schedule without a
+// department. Every course in the semester would need to be
+// fetched one at a time so a department is always needed.
+var ErrNoDepartment = errors.New("a department is needed to get berkeley sections")
+
+func init() {
+	school.Register(Provider)
+}
@@ -0,0 +1,288 @@
+package btime
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+	"github.com/harrybrwn/errs"
+)
+
+// ID returns the section's CCN or zero if
+// the CCN is not a number.
+func (s *Section) ID() int {
+	ccn, err := strconv.Atoi(strings.TrimSpace(s.Ccn))
+	if err != nil {
+		return 0
+	}
+	return ccn
+}
+
+// Name returns the title of the section's course.
+func (s *Section) Name() string {
+	if s.course == nil {
+		return ""
+	}
+	return s.course.Course.Title
+}
+
+// SeatsOpen returns the number of seats left in the section.
+// Sections that are over enrolled have no seats.
+func (s *Section) SeatsOpen() int {
+	if s.Enrolled >= s.EnrolledMax {
+		return 0
+	}
+	return s.EnrolledMax - s.Enrolled
+}
+
+// Code returns the department abbreviation and course number.
+func (s *Section) Code() (subject, number string) {
+	if s.course == nil {
+		return "", ""
+	}
+	return s.course.Course.Abbreviation, s.course.Course.CourseNumber
+}
+
+// SectionType returns the kind of section, i.e. Lecture or Discussion.
+func (s *Section) SectionType() string {
+	return s.Kind
+}
+
+// Sequence returns the section number.
+func (s *Section) Sequence() string {
+	return s.SectionNumber
+}
+
+// Instructors returns the section's instructor.
+func (s *Section) Instructors() []string {
+	if s.Instructor == "" {
+		return nil
+	}
+	return []string{s.Instructor}
+}
+
+// Credits returns the units of the section's course.
+func (s *Section) Credits() float64 {
+	if s.course == nil {
+		return 0
+	}
+	return parseUnits(s.course.Course.Units)
+}
+
+// Enrollment returns the section's enrollment numbers.
+func (s *Section) Enrollment() school.Enrollment {
+	return school.Enrollment{
+		Capacity:   s.EnrolledMax,
+		Enrolled:   s.Enrolled,
+		Waitlisted: s.Waitlisted,
+	}
+}
+
+// MeetingTimes returns the section's meeting time.
+func (s *Section) MeetingTimes() []school.Meeting {
+	m := school.Meeting{
+		Days:       parseWordDays(s.WordDays),
+		Location:   s.LocationName,
+		Instructor: s.Instructor,
+	}
+	m.Start, _ = time.Parse("15:04:05", s.StartTime)
+	m.End, _ = time.Parse("15:04:05", s.EndTime)
+	return []school.Meeting{m}
+}
+
+// parseWordDays parses days in the form "MWF" or "TuTh".
+func parseWordDays(s string) []time.Weekday {
+	var (
+		days   = make([]time.Weekday, 0, 5)
+		prefix = []struct {
+			abbr string
+			day  time.Weekday
+		}{
+			{"Su", time.Sunday},
+			{"Sa", time.Saturday},
+			{"Tu", time.Tuesday},
+			{"Th", time.Thursday},
+			{"M", time.Monday},
+			{"T", time.Tuesday},
+			{"W", time.Wednesday},
+			{"R", time.Thursday},
+			{"F", time.Friday},
+			{"S", time.Saturday},
+		}
+	)
+	s = strings.TrimSpace(s)
+outer:
+	for len(s) > 0 {
+		for _, p := range prefix {
+			if strings.HasPrefix(s, p.abbr) {
+				days = append(days, p.day)
+				s = s[len(p.abbr):]
+				continue outer
+			}
+		}
+		s = s[1:] // skip anything unknown
+	}
+	return days
+}
+
+// link points each section at its course.
+func (c *Course) link() {
+	for i := range c.Sections {
+		c.Sections[i].course = c
+	}
+	for i := range c.OngoingSections {
+		c.OngoingSections[i].course = c
+	}
+}
+
+// Schedule is a set of sections keyed by CCN.
+type Schedule struct {
+	courses  []*Course
+	sections map[int]*Section
+}
+
+// NewSchedule creates a schedule from the sections
+// of a list of courses.
+func NewSchedule(courses []*Course) *Schedule {
+	s := &Schedule{
+		courses:  courses,
+		sections: make(map[int]*Section),
+	}
+	for _, c := range courses {
+		c.link()
+		for i := range c.Sections {
+			sec := &c.Sections[i]
+			if sec.ID() == 0 {
+				continue
+			}
+			s.sections[sec.ID()] = sec
+		}
+	}
+	return s
+}
+
+// Sections will get the sections for all of the
+// courses in the catalog. The course information is
+// fetched using the given number of workers and the
+// context's error is returned if it is done before
+// every course is fetched.
+func (c *Catalog) Sections(ctx context.Context, workers int) (*Schedule, error) {
+	if c.courses == nil {
+		if err := c.Load(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errlist []error
+		courses = make([]*Course, len(c.results))
+		sem     = make(chan struct{}, workers)
+	)
+	for i, r := range c.results {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Add(1)
+		go func(i int, r *Result) {
+			defer func() { <-sem; wg.Done() }()
+			course, err := r.Course(ctx)
+			if err != nil {
+				mu.Lock()
+				errlist = append(errlist, err)
+				mu.Unlock()
+				return
+			}
+			courses[i] = course
+		}(i, r)
+	}
+	wg.Wait()
+	if len(errlist) > 0 {
+		return nil, errs.Chain(errlist...)
+	}
+	return NewSchedule(courses), nil
+}
+
+// RemoveClosed removes any sections that have no open seats.
+func (s *Schedule) RemoveClosed() {
+	for ccn, sec := range s.sections {
+		if sec.SeatsOpen() <= 0 {
+			delete(s.sections, ccn)
+		}
+	}
+}
+
+// Get returns the section with the given CCN.
+func (s *Schedule) Get(ccn int) school.Course {
+	sec, ok := s.sections[ccn]
+	if !ok {
+		return nil
+	}
+	return sec
+}
+
+// Len returns the number of sections.
+func (s *Schedule) Len() int {
+	return len(s.sections)
+}
+
+// Courses returns the sections in the order that
+// they appear in their courses.
+func (s *Schedule) Courses() []school.Course {
+	list := make([]school.Course, 0, len(s.sections))
+	for _, g := range s.SectionGroups() {
+		list = append(list, g.Course)
+		list = append(list, g.Sections...)
+	}
+	return list
+}
+
+// SectionGroups groups each lecture with the rest
+// of the sections in its course.
+func (s *Schedule) SectionGroups() []school.Group {
+	groups := make([]school.Group, 0, len(s.courses))
+	for _, c := range s.courses {
+		var g school.Group
+		for i := range c.Sections {
+			sec := &c.Sections[i]
+			if _, ok := s.sections[sec.ID()]; !ok {
+				continue
+			}
+			if g.Course == nil && isLecture(sec) {
+				g.Course = sec
+			} else {
+				g.Sections = append(g.Sections, sec)
+			}
+		}
+		if g.Course == nil {
+			// no lecture so each section is its own group
+			for _, sec := range g.Sections {
+				groups = append(groups, school.Group{Course: sec})
+			}
+			continue
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+func isLecture(s *Section) bool {
+	kind := strings.ToLower(s.Kind)
+	return strings.HasPrefix(kind, "lec")
+}
+
+var (
+	_ school.Schedule  = (*Schedule)(nil)
+	_ school.Grouper   = (*Schedule)(nil)
+	_ school.Course    = (*Section)(nil)
+	_ school.Sequencer = (*Section)(nil)
+)
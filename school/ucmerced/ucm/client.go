@@ -0,0 +1,173 @@
+package ucm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/errs"
+)
+
+// Client gets schedules from a Banner 8 site. Every request is
+// made with a context so that slow requests can be canceled.
+type Client struct {
+	client *http.Client
+	site   Site
+
+	// UserAgent is sent with every request, a
+	// new one is made for each request if empty.
+	UserAgent string
+}
+
+// NewClient creates a new client for a site.
+func NewClient(site *Site) *Client {
+	return ClientFromClient(site, &http.Client{Timeout: time.Second * 15})
+}
+
+// ClientFromClient creates a client for a site
+// that uses a user given http.Client.
+func ClientFromClient(site *Site, c *http.Client) *Client {
+	return &Client{client: c, site: *site}
+}
+
+// SetTransport sets the transport used by the client.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.client.Transport = rt
+}
+
+// SetTimeout sets the timeout for each request. Use a
+// context for a deadline that covers many requests.
+func (c *Client) SetTimeout(tm time.Duration) {
+	c.client.Timeout = tm
+}
+
+// SetBaseURL sets the url that the client's requests are sent to
+// without changing the site that the client was created with.
+func (c *Client) SetBaseURL(u string) error {
+	return c.site.SetBaseURL(u)
+}
+
+// Site returns the site that the client gets schedules from.
+func (c *Client) Site() Site {
+	return c.site
+}
+
+// Schedule gets the schedule for a term. An empty subject will
+// get every subject and open will only get courses with seats open.
+func (c *Client) Schedule(ctx context.Context, year int, term, subject string, open bool) (Schedule, error) {
+	resp, err := c.getData(ctx, fmt.Sprintf("%d", year), term, strings.ToUpper(subject), open)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errs.New(resp.Status)
+	}
+	rows, err := parseRows(resp.Body, &c.site.Columns)
+	if err != nil {
+		return nil, err
+	}
+	sched, err := parse(rows, year, &c.site.Columns)
+	if err != nil {
+		return nil, err
+	}
+	for _, crs := range sched {
+		crs.client = c
+	}
+	return sched, nil
+}
+
+// Offerings will get the subjects and terms offered
+// from the subject selection page.
+func (c *Client) Offerings(ctx context.Context) (*Offerings, error) {
+	resp, err := c.do(ctx, c.newRequest("/xhwschedule.p_selectsubject", ""))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errs.New(resp.Status)
+	}
+	return parseOfferings(resp.Body, c.site.Terms)
+}
+
+// Info gets extra info for the course from its info page.
+func (c *Client) Info(ctx context.Context, crs *Course) (string, error) {
+	// The info url is relative so we can't just
+	// call url.Parse to get the query
+	var (
+		parts = strings.Split(crs.infoURL, "?")
+		p     string
+		query string
+	)
+	switch len(parts) {
+	case 0:
+		return "", errors.New("bad url")
+	case 2:
+		query = parts[1]
+		fallthrough
+	case 1:
+		p = parts[0]
+	}
+	resp, err := c.do(ctx, c.newRequest(p, query))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errs.New(resp.Status)
+	}
+	return parseInfoPage(resp.Body)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	ua := c.UserAgent
+	if ua == "" {
+		ua = fmt.Sprintf("go-edu-%v", time.Now().Nanosecond())
+	}
+	req.Header.Set("User-Agent", ua)
+	return c.client.Do(req.WithContext(ctx))
+}
+
+// newRequest creates a GET request for a path
+// relative to the base url.
+func (c *Client) newRequest(p, query string) *http.Request {
+	u := c.site.BaseURL
+	u.Path = path.Join(c.site.BaseURL.Path, p)
+	u.RawQuery = query
+	return &http.Request{
+		Method: "GET",
+		Proto:  "HTTP/1.1",
+		URL:    &u,
+		Header: make(http.Header),
+	}
+}
+
+func (c *Client) getData(ctx context.Context, year, term, subject string, openclasses bool) (*http.Response, error) {
+	termcode, ok := c.site.Terms[strings.ToLower(term)]
+	if !ok {
+		return nil, fmt.Errorf("could not find term %s", term)
+	}
+	var open string
+	if openclasses {
+		open = "Y"
+	} else {
+		open = "N"
+	}
+	if subject == "" {
+		subject = "ALL"
+	}
+	params := &url.Values{
+		"validterm":   {fmt.Sprintf("%s%s", year, termcode)},
+		"openclasses": {open},
+		"subjcode":    {strings.ToUpper(subject)},
+	}
+	// TODO change this to POST the params as form data
+	// curl -s -X POST 'https://mystudentrecord.ucmerced.edu/pls/PROD/xhwschedule.P_ViewSchedule' --form validterm=202020 --form openclasses=N
+	return c.do(ctx, c.newRequest("/xhwschedule.P_ViewSchedule", params.Encode()))
+}
@@ -0,0 +1,199 @@
+package ucm
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harrybrwn/errs"
+)
+
+// Description is a course's catalog description.
+type Description struct {
+	Text          string
+	Prerequisites string
+	Corequisites  string
+}
+
+var requisiteRegex = regexp.MustCompile(`(?i)\b(pre|co)-?requisites?(?: courses?)?\s*:?\s*(.+?)(?:\.(?:\s|$)|$)`)
+
+// ParseDescription will parse the prerequisites and
+// corequisites out of a course description.
+func ParseDescription(text string) *Description {
+	d := &Description{Text: strings.TrimSpace(text)}
+	for _, m := range requisiteRegex.FindAllStringSubmatch(d.Text, -1) {
+		req := strings.TrimSpace(m[2])
+		switch strings.ToLower(m[1]) {
+		case "pre":
+			d.Prerequisites = req
+		case "co":
+			d.Corequisites = req
+		}
+	}
+	return d
+}
+
+// Description will get the course's parsed description.
+func (c *Course) Description(ctx context.Context) (*Description, error) {
+	client := c.client
+	if client == nil {
+		client = defaultClient
+	}
+	return client.Description(ctx, c)
+}
+
+// Description will get a course's parsed description.
+func (c *Client) Description(ctx context.Context, crs *Course) (*Description, error) {
+	info, err := c.Info(ctx, crs)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDescription(info), nil
+}
+
+// CourseKey returns the key that identifies all sections
+// of a course, i.e. "CSE-100" for "CSE-100-02L".
+func (c *Course) CourseKey() string {
+	parts := strings.Split(c.Fullcode, "-")
+	if len(parts) < 2 {
+		return c.Fullcode
+	}
+	return parts[0] + "-" + parts[1]
+}
+
+// Descriptions maps course keys (see Course.CourseKey)
+// to the course's description.
+type Descriptions map[string]*Description
+
+// FetchOptions controls how descriptions are downloaded.
+type FetchOptions struct {
+	// Workers is the number of concurrent requests.
+	Workers int
+	// Interval is the minimum time between requests.
+	Interval time.Duration
+	// Progress is called after each description is fetched.
+	Progress func(done, total int)
+}
+
+// Fetch will download the descriptions of every course that
+// is not already in the map. Each course is only downloaded
+// once no matter how many sections it has. If the context is
+// done then the descriptions that were already downloaded are
+// kept in the map and the context's error is returned.
+func (d Descriptions) Fetch(ctx context.Context, courses []*Course, opts FetchOptions) error {
+	var (
+		todo = make([]*Course, 0)
+		seen = make(map[string]bool)
+	)
+	for _, c := range courses {
+		key := c.CourseKey()
+		if _, ok := d[key]; ok || seen[key] || c.infoURL == "" {
+			continue
+		}
+		seen[key] = true
+		todo = append(todo, c)
+	}
+	if len(todo) == 0 {
+		return nil
+	}
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		done    int
+		errList []error
+		jobs    = make(chan *Course)
+		tick    <-chan time.Time
+	)
+	if opts.Interval > 0 {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	wg.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				desc, err := c.Description(ctx)
+				mu.Lock()
+				if err == nil {
+					d[c.CourseKey()] = desc
+				} else if ctx.Err() == nil {
+					// canceled requests are not failures
+					errList = append(errList, err)
+				}
+				done++
+				if opts.Progress != nil {
+					opts.Progress(done, len(todo))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, c := range todo {
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		select {
+		case jobs <- c:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errs.Chain(errList...)
+}
+
+// Search will find the courses whose title or description
+// contain every word in the query. Only one section of
+// each course is returned.
+func (d Descriptions) Search(courses []*Course, query string) []*Course {
+	var (
+		words   = strings.Fields(strings.ToLower(query))
+		results = make([]*Course, 0)
+		seen    = make(map[string]bool)
+	)
+	for _, c := range courses {
+		key := c.CourseKey()
+		if seen[key] {
+			continue
+		}
+		text := strings.ToLower(c.Title)
+		if desc, ok := d[key]; ok {
+			text += " " + strings.ToLower(desc.Text)
+		}
+		if containsAll(text, words) {
+			seen[key] = true
+			results = append(results, c)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CourseKey() < results[j].CourseKey()
+	})
+	return results
+}
+
+func containsAll(text string, words []string) bool {
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
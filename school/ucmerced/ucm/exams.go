@@ -0,0 +1,68 @@
+package ucm
+
+import "sort"
+
+// ExamConflict is a pair of exams that are on the same day.
+type ExamConflict struct {
+	A, B         *Course
+	ExamA, ExamB *Exam
+	// Overlap is true when the exam times overlap,
+	// otherwise the exams are only on the same day.
+	Overlap bool
+}
+
+// ExamConflicts finds every pair of exams from different
+// courses that overlap or are on the same day.
+func ExamConflicts(courses []*Course) []ExamConflict {
+	type courseExam struct {
+		c *Course
+		e *Exam
+	}
+	exams := make([]courseExam, 0, len(courses))
+	for _, c := range courses {
+		for _, e := range courseExams(c) {
+			if e.Date.IsZero() {
+				continue
+			}
+			exams = append(exams, courseExam{c, e})
+		}
+	}
+	sort.SliceStable(exams, func(i, j int) bool {
+		return exams[i].e.Date.Before(exams[j].e.Date)
+	})
+
+	conflicts := make([]ExamConflict, 0)
+	for i, a := range exams {
+		for _, b := range exams[i+1:] {
+			if a.c.CRN == b.c.CRN || !sameDate(a.e, b.e) {
+				continue
+			}
+			conflicts = append(conflicts, ExamConflict{
+				A: a.c, B: b.c, ExamA: a.e, ExamB: b.e,
+				Overlap: examsOverlap(a.e, b.e),
+			})
+		}
+	}
+	return conflicts
+}
+
+func courseExams(c *Course) []*Exam {
+	if len(c.Exams) == 0 && c.Exam != nil {
+		return []*Exam{c.Exam}
+	}
+	return c.Exams
+}
+
+func sameDate(a, b *Exam) bool {
+	ay, am, ad := a.Date.Date()
+	by, bm, bd := b.Date.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func examsOverlap(a, b *Exam) bool {
+	if a.Time.Start.IsZero() || b.Time.Start.IsZero() {
+		return false // TBD
+	}
+	return minutes(a.Time.Start) < minutes(b.Time.End) &&
+		minutes(b.Time.Start) < minutes(a.Time.End)
+}
@@ -0,0 +1,261 @@
+package ucm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	icsDateTime = "20060102T150405"
+	icsProdID   = "-//harrybrwn//edu//EN"
+)
+
+var icsDays = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// WriteICS will write an iCalendar (RFC 5545) file with a weekly
+// recurring event for each meeting of the courses and an event
+// for each final exam.
+//
+// The times in the schedule are local to the school so they are
+// written in loc with a VTIMEZONE that has its daylight saving
+// changes, this keeps weekly events at the same local time all
+// term. If loc is nil then the times are written as floating
+// times which calendars show in the user's own time zone.
+func WriteICS(w io.Writer, courses []*Course, loc *time.Location) error {
+	ics := &icsWriter{w: bufio.NewWriter(w), loc: loc, stamp: time.Now().UTC()}
+	ics.line("BEGIN:VCALENDAR")
+	ics.line("VERSION:2.0")
+	ics.line("PRODID:" + icsProdID)
+	ics.line("CALSCALE:GREGORIAN")
+	if start, end, ok := calendarSpan(courses); ok && loc != nil {
+		ics.timezone(start, end)
+	}
+	for _, c := range courses {
+		for i, m := range courseMeetings(c) {
+			ics.meeting(c, i, &m)
+		}
+		for _, e := range courseExams(c) {
+			ics.exam(c, e)
+		}
+	}
+	ics.line("END:VCALENDAR")
+	if ics.err != nil {
+		return ics.err
+	}
+	return ics.w.Flush()
+}
+
+type icsWriter struct {
+	w     *bufio.Writer
+	loc   *time.Location
+	stamp time.Time
+	err   error
+}
+
+func (ics *icsWriter) meeting(c *Course, i int, m *Meeting) {
+	if m.Time.Start.IsZero() || len(m.Days) == 0 || m.Date.Start.IsZero() {
+		return // TBD
+	}
+	// find the first day of class
+	first := m.Date.Start
+	for n := 0; n < 7 && !hasDay(m.Days, first.Weekday()); n++ {
+		first = first.AddDate(0, 0, 1)
+	}
+	days := make([]string, len(m.Days))
+	for j, d := range m.Days {
+		days[j] = icsDays[d]
+	}
+	until := ics.until(m.Date.End)
+
+	ics.line("BEGIN:VEVENT")
+	ics.line(fmt.Sprintf("UID:%d-%d@edu", c.CRN, i))
+	ics.line("DTSTAMP:" + ics.stamp.Format(icsDateTime) + "Z")
+	ics.line("DTSTART" + ics.date(first, m.Time.Start.Hour(), m.Time.Start.Minute()))
+	ics.line("DTEND" + ics.date(first, m.Time.End.Hour(), m.Time.End.Minute()))
+	ics.line(fmt.Sprintf("RRULE:FREQ=WEEKLY;BYDAY=%s;UNTIL=%s", strings.Join(days, ","), until))
+	ics.line("SUMMARY:" + icsEscape(fmt.Sprintf("%s %s", c.Fullcode, m.Activity)))
+	if m.BuildingRoom != "" {
+		ics.line("LOCATION:" + icsEscape(m.BuildingRoom))
+	}
+	ics.line("DESCRIPTION:" + icsEscape(fmt.Sprintf("%s\nCRN: %d\nInstructor: %s", c.Title, c.CRN, m.Instructor)))
+	ics.line("END:VEVENT")
+}
+
+func (ics *icsWriter) exam(c *Course, e *Exam) {
+	if e.Date.IsZero() || e.Time.Start.IsZero() {
+		return
+	}
+	ics.line("BEGIN:VEVENT")
+	ics.line(fmt.Sprintf("UID:%d-exam-%s@edu", c.CRN, e.Date.Format("20060102")))
+	ics.line("DTSTAMP:" + ics.stamp.Format(icsDateTime) + "Z")
+	ics.line("DTSTART" + ics.date(e.Date, e.Time.Start.Hour(), e.Time.Start.Minute()))
+	ics.line("DTEND" + ics.date(e.Date, e.Time.End.Hour(), e.Time.End.Minute()))
+	ics.line("SUMMARY:" + icsEscape(fmt.Sprintf("%s Final Exam", c.Fullcode)))
+	if e.Building != "" {
+		ics.line("LOCATION:" + icsEscape(e.Building))
+	}
+	ics.line("DESCRIPTION:" + icsEscape(fmt.Sprintf("%s\nCRN: %d", c.Title, c.CRN)))
+	ics.line("END:VEVENT")
+}
+
+// date formats the date at a time of day as the parameters
+// and value of a date-time property, i.e. ";TZID=...:<time>".
+func (ics *icsWriter) date(day time.Time, hour, min int) string {
+	t := time.Date(day.Year(), day.Month(), day.Day(), hour, min, 0, 0, time.UTC)
+	if ics.loc == nil {
+		return ":" + t.Format(icsDateTime)
+	}
+	return ";TZID=" + ics.loc.String() + ":" + t.Format(icsDateTime)
+}
+
+// until formats the end of the last day for an RRULE. It is
+// in UTC when there is a time zone because RFC 5545 does not
+// allow UNTIL to have a TZID.
+func (ics *icsWriter) until(day time.Time) string {
+	if ics.loc == nil {
+		t := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 0, 0, time.UTC)
+		return t.Format(icsDateTime)
+	}
+	t := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 0, 0, ics.loc)
+	return t.UTC().Format(icsDateTime) + "Z"
+}
+
+type observance struct {
+	onset    time.Time
+	name     string
+	from, to int
+}
+
+// timezone writes a VTIMEZONE with every utc offset
+// change of the location between two dates.
+func (ics *icsWriter) timezone(start, end time.Time) {
+	var (
+		t        = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, ics.loc)
+		stop     = time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, ics.loc)
+		name, to = t.Zone()
+		obs      = []observance{{onset: t, name: name, from: to, to: to}}
+		std      = to
+	)
+	for t.Before(stop) {
+		next := t.Add(time.Hour)
+		if _, off := next.Zone(); off != to {
+			// find the minute of the change
+			for _, off = t.Zone(); off == to; _, off = t.Zone() {
+				t = t.Add(time.Minute)
+			}
+			name, off = t.Zone()
+			obs = append(obs, observance{onset: t, name: name, from: to, to: off})
+			to = off
+			if off < std {
+				std = off
+			}
+			next = t
+		}
+		t = next
+	}
+
+	ics.line("BEGIN:VTIMEZONE")
+	ics.line("TZID:" + ics.loc.String())
+	for _, o := range obs {
+		kind := "STANDARD"
+		if o.to > std {
+			kind = "DAYLIGHT"
+		}
+		ics.line("BEGIN:" + kind)
+		// onsets are the local time before the change
+		ics.line("DTSTART:" + o.onset.In(time.FixedZone("", o.from)).Format(icsDateTime))
+		ics.line("TZOFFSETFROM:" + icsOffset(o.from))
+		ics.line("TZOFFSETTO:" + icsOffset(o.to))
+		ics.line("TZNAME:" + icsEscape(o.name))
+		ics.line("END:" + kind)
+	}
+	ics.line("END:VTIMEZONE")
+}
+
+func icsOffset(secs int) string {
+	sign := '+'
+	if secs < 0 {
+		sign, secs = '-', -secs
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, secs/3600, secs/60%60)
+}
+
+// calendarSpan returns the first and last dates of
+// the meetings and exams of the courses.
+func calendarSpan(courses []*Course) (start, end time.Time, ok bool) {
+	add := func(t time.Time) {
+		if t.IsZero() {
+			return
+		}
+		if !ok || t.Before(start) {
+			start = t
+		}
+		if !ok || t.After(end) {
+			end = t
+		}
+		ok = true
+	}
+	for _, c := range courses {
+		for _, m := range courseMeetings(c) {
+			add(m.Date.Start)
+			add(m.Date.End)
+		}
+		for _, e := range courseExams(c) {
+			add(e.Date)
+		}
+	}
+	return start, end, ok
+}
+
+// line writes a content line folded at 75 octets.
+func (ics *icsWriter) line(s string) {
+	if ics.err != nil {
+		return
+	}
+	limit := 75
+	for len(s) > limit {
+		n := limit
+		// don't split utf-8 characters
+		for n > 0 && s[n]&0xC0 == 0x80 {
+			n--
+		}
+		_, ics.err = ics.w.WriteString(s[:n] + "\r\n ")
+		s = s[n:]
+		limit = 74 // continuation lines start with a space
+	}
+	_, err := ics.w.WriteString(s + "\r\n")
+	if ics.err == nil {
+		ics.err = err
+	}
+}
+
+var icsEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\n", `\n`,
+)
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+func hasDay(days []time.Weekday, d time.Weekday) bool {
+	for _, day := range days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,353 @@
+package ucm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+// Criterion is a way of ranking schedule options.
+type Criterion string
+
+// The ranking criteria.
+const (
+	// ByEarly ranks by the fewest classes before Preferences.Earliest
+	ByEarly Criterion = "early"
+	// ByDays ranks by the fewest days on campus
+	ByDays Criterion = "days"
+	// ByGaps ranks by the least time between classes
+	ByGaps Criterion = "gaps"
+	// ByInstructor ranks by the most preferred instructors
+	ByInstructor Criterion = "instructor"
+)
+
+// DefaultRanking is the ranking used when
+// Preferences.Rank is empty.
+var DefaultRanking = []Criterion{ByEarly, ByInstructor, ByDays, ByGaps}
+
+// ParseCriterion will parse the name of a ranking criterion.
+func ParseCriterion(s string) (Criterion, error) {
+	c := Criterion(strings.ToLower(strings.TrimSpace(s)))
+	if !c.valid() {
+		return "", fmt.Errorf("unknown ranking criterion %q, use one of early, instructor, days, or gaps", s)
+	}
+	return c, nil
+}
+
+func (c Criterion) valid() bool {
+	switch c {
+	case ByEarly, ByDays, ByGaps, ByInstructor:
+		return true
+	}
+	return false
+}
+
+// Preferences are used to rank schedule options.
+type Preferences struct {
+	// Earliest is the hour that classes should not start
+	// before. Zero means any time is fine.
+	Earliest int
+	// Instructors is a list of preferred instructor names.
+	Instructors []string
+	// Rank is the order that the criteria are compared in.
+	Rank []Criterion
+	// Limit is the maximum number of options returned.
+	Limit int
+}
+
+// Option is a set of sections that do not conflict.
+type Option struct {
+	Sections []*Course
+
+	// Early is the number of meetings that start
+	// before the earliest preferred hour.
+	Early int
+	// Days is the number of days on campus.
+	Days int
+	// Gaps is the time spent between classes each week.
+	Gaps time.Duration
+	// Preferred is the number of sections taught
+	// by a preferred instructor.
+	Preferred int
+}
+
+// CRNs returns the CRNs of every section in the option.
+func (o *Option) CRNs() []int {
+	crns := make([]int, len(o.Sections))
+	for i, s := range o.Sections {
+		crns[i] = s.CRN
+	}
+	return crns
+}
+
+// Plan will find every combination of sections for a list of
+// courses that do not overlap and rank them by the preferences.
+// Courses are parsed with school.ParseCourseIdent. Only the best
+// options are kept while searching when there is a limit.
+func (s *Schedule) Plan(courses []string, prefs Preferences) ([]Option, error) {
+	rank := prefs.Rank
+	if len(rank) == 0 {
+		rank = DefaultRanking
+	}
+	for _, c := range rank {
+		if !c.valid() {
+			return nil, fmt.Errorf("unknown ranking criterion %q", c)
+		}
+	}
+	choices := make([][][]*Course, len(courses))
+	for i, id := range courses {
+		ident, err := school.ParseCourseIdent(id)
+		if err != nil {
+			return nil, err
+		}
+		choices[i] = s.bundles(ident)
+		if len(choices[i]) == 0 {
+			return nil, fmt.Errorf("no sections found for %s", id)
+		}
+	}
+
+	var (
+		options = make([]Option, 0)
+		chosen  = make([]*Course, 0, len(courses)*2)
+		search  func(i int)
+	)
+	search = func(i int) {
+		if i == len(choices) {
+			opt := newOption(chosen, &prefs)
+			// options are kept in order, equal options stay in
+			// the order that they were found
+			at := sort.Search(len(options), func(j int) bool {
+				return less(&opt, &options[j], rank)
+			})
+			if prefs.Limit > 0 && at >= prefs.Limit {
+				return
+			}
+			opt.Sections = make([]*Course, len(chosen))
+			copy(opt.Sections, chosen)
+			options = append(options, Option{})
+			copy(options[at+1:], options[at:])
+			options[at] = opt
+			if prefs.Limit > 0 && len(options) > prefs.Limit {
+				options = options[:prefs.Limit]
+			}
+			return
+		}
+		for _, bundle := range choices[i] {
+			if conflicts(chosen, bundle) {
+				continue
+			}
+			chosen = append(chosen, bundle...)
+			search(i + 1)
+			chosen = chosen[:len(chosen)-len(bundle)]
+		}
+	}
+	search(0)
+	return options, nil
+}
+
+// bundles returns every set of sections that could be registered
+// for to take a course. A lecture with linked sections needs one
+// section of each linked activity. If the course has a lecture
+// then sections that are not linked to one are not enough to
+// take the course on their own.
+func (s *Schedule) bundles(id school.CourseIdent) [][]*Course {
+	var (
+		bundles = make([][]*Course, 0)
+		course  = school.CourseIdent{Subject: id.Subject, Number: id.Number}
+		lecture bool
+	)
+	for _, g := range s.Groups() {
+		c := g.Course
+		if !course.Match(c) {
+			continue
+		}
+		if isLecture(c) {
+			lecture = true
+		}
+		if len(g.Sections) == 0 {
+			bundles = append(bundles, []*Course{c})
+			continue
+		}
+		// group the linked sections by activity
+		var (
+			activities = make([]string, 0)
+			byActivity = make(map[string][]*Course)
+		)
+		for _, sec := range g.Sections {
+			if _, ok := byActivity[sec.Activity]; !ok {
+				activities = append(activities, sec.Activity)
+			}
+			byActivity[sec.Activity] = append(byActivity[sec.Activity], sec)
+		}
+		combos := [][]*Course{{c}}
+		for _, act := range activities {
+			next := make([][]*Course, 0, len(combos)*len(byActivity[act]))
+			for _, combo := range combos {
+				for _, sec := range byActivity[act] {
+					b := make([]*Course, len(combo), len(combo)+1)
+					copy(b, combo)
+					next = append(next, append(b, sec))
+				}
+			}
+			combos = next
+		}
+		bundles = append(bundles, combos...)
+	}
+	if !lecture {
+		return bundles
+	}
+	withLecture := bundles[:0]
+	for _, b := range bundles {
+		for _, c := range b {
+			if isLecture(c) {
+				withLecture = append(withLecture, b)
+				break
+			}
+		}
+	}
+	return withLecture
+}
+
+func isLecture(c *Course) bool {
+	return c.Activity == string(Lecture)
+}
+
+func conflicts(chosen, bundle []*Course) bool {
+	for i, a := range bundle {
+		// sections within the bundle can conflict too
+		for _, b := range bundle[i+1:] {
+			if overlaps(a, b) {
+				return true
+			}
+		}
+		for _, b := range chosen {
+			if overlaps(a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// overlaps returns true if any of the meetings for two
+// courses happen at the same time.
+func overlaps(a, b *Course) bool {
+	for _, ma := range courseMeetings(a) {
+		for _, mb := range courseMeetings(b) {
+			if meetingsOverlap(&ma, &mb) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func courseMeetings(c *Course) []Meeting {
+	if len(c.Meetings) > 0 {
+		return c.Meetings
+	}
+	return []Meeting{{Days: c.Days, Time: c.Time, Date: c.Date}}
+}
+
+func meetingsOverlap(a, b *Meeting) bool {
+	if a.Time.Start.IsZero() || b.Time.Start.IsZero() {
+		return false // TBD
+	}
+	if !a.Date.Start.IsZero() && !b.Date.Start.IsZero() &&
+		(a.Date.End.Before(b.Date.Start) || b.Date.End.Before(a.Date.Start)) {
+		return false
+	}
+	if !shareDay(a.Days, b.Days) {
+		return false
+	}
+	return minutes(a.Time.Start) < minutes(b.Time.End) &&
+		minutes(b.Time.Start) < minutes(a.Time.End)
+}
+
+func shareDay(a, b []time.Weekday) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func minutes(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+func newOption(sections []*Course, prefs *Preferences) Option {
+	var (
+		opt   = Option{Sections: sections}
+		byDay = make(map[time.Weekday][]Meeting)
+	)
+	for _, c := range sections {
+		if isPreferred(c, prefs.Instructors) {
+			opt.Preferred++
+		}
+		for _, m := range courseMeetings(c) {
+			if m.Time.Start.IsZero() {
+				continue
+			}
+			if prefs.Earliest > 0 && m.Time.Start.Hour() < prefs.Earliest {
+				opt.Early++
+			}
+			for _, d := range m.Days {
+				byDay[d] = append(byDay[d], m)
+			}
+		}
+	}
+	opt.Days = len(byDay)
+	for _, meetings := range byDay {
+		sort.Slice(meetings, func(i, j int) bool {
+			return minutes(meetings[i].Time.Start) < minutes(meetings[j].Time.Start)
+		})
+		for i := 1; i < len(meetings); i++ {
+			gap := minutes(meetings[i].Time.Start) - minutes(meetings[i-1].Time.End)
+			if gap > 0 {
+				opt.Gaps += time.Duration(gap) * time.Minute
+			}
+		}
+	}
+	return opt
+}
+
+func isPreferred(c *Course, instructors []string) bool {
+	name := strings.ToLower(c.Instructor)
+	for _, inst := range instructors {
+		if inst != "" && strings.Contains(name, strings.ToLower(inst)) {
+			return true
+		}
+	}
+	return false
+}
+
+func less(a, b *Option, rank []Criterion) bool {
+	for _, c := range rank {
+		switch c {
+		case ByEarly:
+			if a.Early != b.Early {
+				return a.Early < b.Early
+			}
+		case ByDays:
+			if a.Days != b.Days {
+				return a.Days < b.Days
+			}
+		case ByGaps:
+			if a.Gaps != b.Gaps {
+				return a.Gaps < b.Gaps
+			}
+		case ByInstructor:
+			if a.Preferred != b.Preferred {
+				return a.Preferred > b.Preferred
+			}
+		}
+	}
+	return false
+}
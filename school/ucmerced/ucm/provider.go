@@ -0,0 +1,71 @@
+package ucm
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+// Provider is the UC Merced school provider.
+var Provider = Merced.Provider("ucmerced", "UC Merced", "merced", "ucm")
+
+var (
+	sitesMu sync.Mutex
+	sites   = make(map[*school.Provider]*Site)
+)
+
+// ProviderSite returns the site that a provider gets its schedules
+// from. Nil is returned if the provider was not made by a site.
+func ProviderSite(p *school.Provider) *Site {
+	sitesMu.Lock()
+	defer sitesMu.Unlock()
+	return sites[p]
+}
+
+// Provider creates a school provider that gets
+// schedules from the site.
+func (s *Site) Provider(name, title string, aliases ...string) *school.Provider {
+	p := s.provider(name, title, aliases...)
+	sitesMu.Lock()
+	sites[p] = s
+	sitesMu.Unlock()
+	return p
+}
+
+func (s *Site) provider(name, title string, aliases ...string) *school.Provider {
+	return &school.Provider{
+		Name:     name,
+		Aliases:  aliases,
+		Title:    title,
+		BaseURL:  s.BaseURL.String(),
+		Terms:    s.Terms,
+		TimeZone: s.TimeZone,
+		Capabilities: school.Seats |
+			school.Meetings |
+			school.SectionLinks |
+			school.Offerings,
+		New: func(ctx context.Context, conf *school.Config) (school.Schedule, error) {
+			sched, err := ClientFromClient(s, &client).Schedule(ctx, conf.Year, conf.Term, conf.CourseName, conf.FilterClosed)
+			if err != nil {
+				return nil, err
+			}
+			return &sched, nil
+		},
+		Check: func(ctx context.Context, conf *school.Config) error {
+			offered, err := ClientFromClient(s, &client).Offerings(ctx)
+			if err != nil {
+				// the schedule may still work if the
+				// subject page is down so don't fail
+				log.Printf("could not get offered subjects and terms: %v\n", err)
+				return nil
+			}
+			return offered.Check(conf.Year, conf.Term, conf.CourseName)
+		},
+	}
+}
+
+func init() {
+	school.Register(Provider)
+}
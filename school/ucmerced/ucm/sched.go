@@ -1,13 +1,12 @@
 package ucm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"net/url"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -15,12 +14,10 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/harrybrwn/edu/school"
 	"github.com/harrybrwn/errs"
+	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
 )
 
-// TODO get subject and terms from
-// 	    here https://mystudentrecord.ucmerced.edu/pls/PROD/xhwschedule.p_selectsubject
-
 const selector = "div.pagebodydiv table.datadisplaytable tr"
 
 var terms = map[string]string{
@@ -60,19 +57,13 @@ type Schedule map[int]*Course
 
 // NewSchedule will return a new schedule based on the config.
 func NewSchedule(config ScheduleConfig) (Schedule, error) {
-	sched, err := getSchedule(config.Year, config.Term, config.Subject, config.Open)
+	sched, err := BySubject(config.Year, config.Term, config.Subject, config.Open)
 	if err != nil {
 		return nil, err
 	}
 	return sched, nil
 }
 
-// SubjectsOffered will pull down the courses offered for a semester
-func SubjectsOffered(config ScheduleConfig) (map[string]string, error) {
-	var subjects = make(map[string]string)
-	return subjects, errors.New("not implemented")
-}
-
 // Get will get a course given the course id
 func (s *Schedule) Get(id int) school.Course {
 	c, ok := (*s)[id]
@@ -123,8 +114,22 @@ type Course struct {
 
 	// Course title
 	Title string
-
+	// Note is the "Must Also Register for..." text that
+	// is found at the end of some course titles.
+	Note string
+
+	// Lecture is the CRN of the lecture that this section
+	// must be registered with. It is zero for lectures and
+	// sections that are not linked to a lecture.
+	Lecture int
+	// Linked is a list of CRNs for the labs, discussions,
+	// etc. that must be registered with this lecture.
+	Linked []int
+
+	// Exam is the course's first exam, see Exams
+	// for courses with more than one.
 	Exam     *Exam
+	Exams    []*Exam
 	Units    int
 	Activity string // TODO this should be changed to "Type CourseType"
 	Days     []time.Weekday
@@ -138,6 +143,11 @@ type Course struct {
 	}
 	Instructor string
 
+	// Meetings is every meeting block for the course. The first
+	// meeting is always the same as the Days, Time, BuildingRoom,
+	// Date, and Instructor fields.
+	Meetings []Meeting
+
 	Capacity int
 	Enrolled int
 
@@ -145,6 +155,23 @@ type Course struct {
 	seats   string
 	order   int
 	infoURL string
+	client  *Client
+}
+
+// Meeting is one meeting block of a course. Most courses only
+// meet once a week but some labs and lectures have a second
+// set of days, times, or rooms.
+type Meeting struct {
+	Activity string
+	Days     []time.Weekday
+	Time     struct {
+		Start, End time.Time
+	}
+	BuildingRoom string
+	Date         struct {
+		Start, End time.Time
+	}
+	Instructor string
 }
 
 // Exam is a course exam
@@ -173,6 +200,97 @@ func (c *Course) CourseNumber() int {
 	return c.Number
 }
 
+// Code returns the subject code and course number
+// as they appear in the full course code.
+func (c *Course) Code() (subject, number string) {
+	parts := strings.Split(c.Fullcode, "-")
+	if len(parts) < 2 {
+		return c.Subject, strconv.Itoa(c.Number)
+	}
+	return parts[0], parts[1]
+}
+
+// SectionType returns the course's activity.
+func (c *Course) SectionType() string {
+	return c.Activity
+}
+
+// Sequence returns the course's section number.
+func (c *Course) Sequence() string {
+	return c.Section
+}
+
+// Instructors returns the instructors of every meeting.
+func (c *Course) Instructors() []string {
+	names := make([]string, 0, 1)
+	for _, m := range courseMeetings(c) {
+		if m.Instructor != "" && !contains(names, m.Instructor) {
+			names = append(names, m.Instructor)
+		}
+	}
+	if len(names) == 0 && c.Instructor != "" {
+		names = append(names, c.Instructor)
+	}
+	return names
+}
+
+// Credits returns the number of units.
+func (c *Course) Credits() float64 {
+	return float64(c.Units)
+}
+
+// Enrollment returns the course's enrollment numbers.
+// UC Merced does not publish waitlists.
+func (c *Course) Enrollment() school.Enrollment {
+	return school.Enrollment{
+		Capacity: c.Capacity,
+		Enrolled: c.Enrolled,
+	}
+}
+
+// FinalExam returns the course's exam, the day
+// of the exam is the meeting's start and end date.
+func (c *Course) FinalExam() *school.Meeting {
+	if c.Exam == nil {
+		return nil
+	}
+	return &school.Meeting{
+		Days:      []time.Weekday{c.Exam.Day},
+		Start:     c.Exam.Time.Start,
+		End:       c.Exam.Time.End,
+		Location:  c.Exam.Building,
+		StartDate: c.Exam.Date,
+		EndDate:   c.Exam.Date,
+	}
+}
+
+// MeetingTimes returns the meetings as generic meetings.
+func (c *Course) MeetingTimes() []school.Meeting {
+	meetings := courseMeetings(c)
+	list := make([]school.Meeting, len(meetings))
+	for i, m := range meetings {
+		list[i] = school.Meeting{
+			Days:       m.Days,
+			Start:      m.Time.Start,
+			End:        m.Time.End,
+			Location:   m.BuildingRoom,
+			Instructor: m.Instructor,
+			StartDate:  m.Date.Start,
+			EndDate:    m.Date.End,
+		}
+	}
+	return list
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
 // SeatsOpen gets the number of seats available for the course.
 func (c *Course) SeatsOpen() int {
 	seats, err := strconv.Atoi(c.seats)
@@ -186,72 +304,46 @@ func (c *Course) SeatsOpen() int {
 
 // Info get extra info for the course
 func (c *Course) Info() (string, error) {
-	// The info url is relative so we can't just
-	// call url.Parse to get the query
-	var (
-		parts = strings.Split(c.infoURL, "?")
-		path  string
-		query string
-	)
-	switch len(parts) {
-	case 0:
-		return "", errors.New("bad url")
-	case 2:
-		query = parts[1]
-		fallthrough
-	case 1:
-		path = parts[0]
-	}
-	req := &http.Request{
-		Method: "GET",
-		Proto:  "HTTP/1.1",
-		URL: &url.URL{
-			Scheme:   "https",
-			Host:     "mystudentrecord.ucmerced.edu",
-			Path:     filepath.Join(basePath, path),
-			RawQuery: query,
-		},
-	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
+	client := c.client
+	if client == nil {
+		client = defaultClient
 	}
-	defer resp.Body.Close()
-	return parseInfoPage(resp.Body)
+	return client.Info(context.Background(), c)
 }
 
 // Get gets the schedule
 func Get(year int, term string, open bool) (Schedule, error) {
-	return getSchedule(year, term, "", open)
+	return defaultClient.Schedule(context.Background(), year, term, "", open)
 }
 
 // BySubject gets the schedule and only one subject given a subject code.
 func BySubject(year int, term, subject string, open bool) (Schedule, error) {
-	return getSchedule(year, term, subject, open)
-}
-
-func getSchedule(year int, term, subject string, open bool) (Schedule, error) {
-	resp, err := getData(fmt.Sprintf("%d", year), term, strings.ToUpper(subject), open)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, errs.New(resp.Status)
-	}
-	rows, err := parseRows(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	return parse(rows, year)
+	return defaultClient.Schedule(context.Background(), year, term, subject, open)
 }
 
 var (
 	errNotACourse   = errors.New("not a course")
 	errPrevNotFound = errors.New("crn not found in previous html element")
+	errPrevSkipped  = errors.New("previous course was skipped")
 )
 
-func parse(rows []*row, year int) (Schedule, error) {
+// prevCourse will backtrack from row i to find the crn of
+// the course that the row belongs to.
+func prevCourse(rows []*row, i int) (int, error) {
+	for j := i - 1; j >= 0; j-- {
+		switch rows[j].kind {
+		case kindHeader:
+			return 0, errPrevNotFound
+		case kindCourse:
+			return rows[j].crn, nil
+		case kindSkip:
+			return 0, errPrevSkipped
+		}
+	}
+	return 0, errPrevNotFound
+}
+
+func parse(rows []*row, year int, cols *Columns) (Schedule, error) {
 	var (
 		length = len(rows)
 		sch    = make(Schedule, length)
@@ -260,49 +352,48 @@ func parse(rows []*row, year int) (Schedule, error) {
 		order  = 0
 	)
 
-Outer:
 	for i := 0; i < length; i++ {
 		var course Course
 		switch rows[i].kind {
-		case kindHeader:
+		case kindHeader, kindSkip:
 			// Skip headers, might want to check
 			// that things have not changed just
 			// to future proof the parser.
 			continue
 		case kindExam:
-			exam, err := parseExam(rows[i].values, year)
+			exam, err := parseExam(rows[i].values, year, cols)
 			if err != nil {
 				return nil, err
 			}
-			// backtrack to find the last course
-			// for this exam
-			for j := i; i > 0; j-- {
-				switch rows[j].kind {
-				case kindHeader:
-					return nil, errors.New("could not find exam's course crn")
-				case kindCourse:
-					// TODO check for multiple exams
-					sch[rows[j].crn].Exam = exam
-					continue Outer
-				default:
-				}
+			crn, err := prevCourse(rows, i)
+			if err == errPrevSkipped {
+				continue
+			} else if err != nil {
+				return nil, errors.New("could not find exam's course crn")
+			}
+			c := sch[crn]
+			if c.Exam == nil {
+				c.Exam = exam
+			}
+			c.Exams = append(c.Exams, exam)
+		case kindMultiLab, kindMultiLect, kindDiscussion:
+			// These rows are extra meeting times for
+			// the last course that was parsed.
+			crn, err := prevCourse(rows, i)
+			if err == errPrevSkipped {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			c := sch[crn]
+			m, err := parseMeeting(rows[i].values, year, c, cols)
+			if err != nil {
+				return nil, err
 			}
-		case kindMultiLab:
-			// TODO handle multiple lab section times
-			//
-			// This is goint to require support for multiple
-			// start and end times, which is going to be annoying
-			// because the only thing I can think of is changing
-			// the date field to a slice of time.Time which will be
-			// a huge compatibility issue (good thing no one uses
-			// this but me)
-		case kindMultiLect:
-			// TODO handle multiple lectures times
-			// see above.
-		case kindDiscussion:
+			c.Meetings = append(c.Meetings, *m)
 		case kindCourse:
 			row = rows[i]
-			_, err = newCourse(&course, row.values, year)
+			_, err = newCourse(&course, row.values, year, cols)
 			if err != nil {
 				return nil, err
 			}
@@ -314,6 +405,7 @@ Outer:
 			return nil, errors.New("invalid row kind")
 		}
 	}
+	link(sch)
 	return sch, nil
 }
 
@@ -328,8 +420,19 @@ const (
 	// lab has multiple meeting times
 	kindMultiLab
 	kindDiscussion
+	// a row that could not be parsed
+	kindSkip
 )
 
+// rowKinds are the first values of rows that
+// are not courses.
+var rowKinds = map[string]bool{
+	"EXAM": true,
+	"LAB":  true,
+	"LECT": true,
+	"DISC": true,
+}
+
 type row struct {
 	kind    uint8
 	infoURL string
@@ -340,7 +443,7 @@ type row struct {
 // parseRows takes the raw data and gets the
 // table and cleans up each row. Its almost like
 // a lexical analysis step.
-func parseRows(r io.Reader) ([]*row, error) {
+func parseRows(r io.Reader, cols *Columns) ([]*row, error) {
 	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
 		return nil, err
@@ -357,11 +460,11 @@ func parseRows(r io.Reader) ([]*row, error) {
 		var row = &row{}
 		header := s.Find("th.ddlabel p small")
 		if header.Length() != 0 {
-			keys = make([]string, 13)
-			for j, n := range header.Nodes {
-				keys[j] = strings.Replace(n.FirstChild.Data, " ", "", -1)
+			keys = make([]string, 0, cols.Count)
+			for _, n := range header.Nodes {
+				keys = append(keys, strings.Replace(nodeText(n), " ", "", -1))
 			}
-			if len(keys) != 13 {
+			if len(keys) != cols.Count {
 				keyerr = errs.New("the wrong number of columns were found in the document")
 			}
 			row.kind = kindHeader
@@ -370,21 +473,28 @@ func parseRows(r io.Reader) ([]*row, error) {
 		}
 
 		var (
-			values  = make([]string, 0, 13)
+			values  = make([]string, 0, cols.Count)
 			courses = s.Find("td.dddefault small")
 		)
 
+		if courses.Length() == 0 {
+			return // empty spacer row
+		}
 		nd := courses.Nodes[0].FirstChild
-		if nd.DataAtom == atom.A && len(nd.Attr) > 0 && nd.Attr[0].Key == "href" {
+		if nd != nil && nd.DataAtom == atom.A && len(nd.Attr) > 0 && nd.Attr[0].Key == "href" {
 			row.infoURL = nd.Attr[0].Val
-			values = append(values, nd.FirstChild.Data)
+			values = append(values, nodeText(nd))
 			courses.Nodes = courses.Nodes[1:]
 		}
 		for _, n := range courses.Nodes {
-			values = append(values, n.FirstChild.Data)
+			values = append(values, nodeText(n))
 		}
 
-		switch values[0] {
+		first := values[0]
+		if cols.CRN < len(values) && !rowKinds[first] {
+			first = values[cols.CRN]
+		}
+		switch first {
 		case "EXAM":
 			row.kind = kindExam // mark as an exam row
 		case "LAB":
@@ -397,13 +507,14 @@ func parseRows(r io.Reader) ([]*row, error) {
 			// Multiple discussion sections
 			row.kind = kindDiscussion
 		default: // otherwise we will just get a CRN
-			crn, e := strconv.ParseInt(values[0], 10, 32)
+			crn, e := strconv.ParseInt(first, 10, 32)
 			if e != nil {
-				if err == nil {
-					err = e
-				}
-				log.Printf("could not parse crn: %v", values[0]) // this sometimes causes problems
-				return
+				// Keep the row so that any exams or meeting
+				// times that follow are not given to the
+				// wrong course.
+				log.Printf("could not parse crn: %v", first) // this sometimes causes problems
+				row.kind = kindSkip
+				break
 			}
 			row.crn = int(crn)
 		}
@@ -416,40 +527,48 @@ func parseRows(r io.Reader) ([]*row, error) {
 	return rows, nil
 }
 
-func newCourse(c *Course, data []string, year int) (*Course, error) {
-	if len(data) != 13 {
+// nodeText returns the text of a node's first child.
+func nodeText(n *html.Node) string {
+	if n.FirstChild == nil {
+		return ""
+	}
+	return n.FirstChild.Data
+}
+
+func newCourse(c *Course, data []string, year int, cols *Columns) (*Course, error) {
+	if len(data) != cols.Count {
 		return nil, errNotACourse
 	}
-	crn, err := strconv.Atoi(data[0])
+	crn, err := strconv.Atoi(data[cols.CRN])
 	if err != nil {
 		return nil, fmt.Errorf("could not parse crn: %w", err)
 	}
-	units, err := strconv.Atoi(data[3])
+	units, err := strconv.Atoi(data[cols.Units])
 	if err != nil {
 		return nil, fmt.Errorf("could not parse units: %w", err)
 	}
-	capacity, err := strconv.Atoi(data[10])
+	capacity, err := strconv.Atoi(data[cols.Capacity])
 	if err != nil {
 		return nil, fmt.Errorf("could not parse max enrollment: %w", err)
 	}
-	activenrl, err := strconv.Atoi(data[11])
+	activenrl, err := strconv.Atoi(data[cols.Enrolled])
 	if err != nil {
 		return nil, fmt.Errorf("could not parse active enrollment: %w", err)
 	}
-	timeStr := data[6]
+	timeStr := data[cols.Time]
 	c.CRN = crn
-	c.Fullcode = data[1]
-	c.Title = data[2]
+	c.Fullcode = data[cols.Code]
+	c.Title, c.Note = splitNote(data[cols.Title])
 	c.Units = units
-	c.Activity = data[4]
-	c.Days = listDays(data[5])
-	c.BuildingRoom = data[7]
-	c.Instructor = data[9]
+	c.Activity = data[cols.Activity]
+	c.Days = listDays(data[cols.Days])
+	c.BuildingRoom = data[cols.Room]
+	c.Instructor = data[cols.Instructor]
 	c.Capacity = capacity
 	c.Enrolled = activenrl
-	c.seats = data[12]
+	c.seats = data[cols.Seats]
 
-	date, err := parseDateRange(data[8], year)
+	date, err := parseDateRange(data[cols.Dates], year)
 	if err != nil {
 		return nil, err
 	}
@@ -458,6 +577,14 @@ func newCourse(c *Course, data []string, year int) (*Course, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.Meetings = []Meeting{{
+		Activity:     c.Activity,
+		Days:         c.Days,
+		Time:         c.Time,
+		BuildingRoom: c.BuildingRoom,
+		Date:         c.Date,
+		Instructor:   c.Instructor,
+	}}
 	// parsing the course number from the course code
 	parts := strings.Split(c.Fullcode, "-")
 	if len(parts) >= 3 {
@@ -476,19 +603,79 @@ func newCourse(c *Course, data []string, year int) (*Course, error) {
 	return c, nil
 }
 
-func parseExam(values []string, year int) (*Exam, error) {
-	var (
-		err error
-	)
-	exam := &Exam{
-		Day:      listDays(values[1])[0],
-		Building: values[3],
+// meetingRow holds the values of an extra meeting time or exam
+// row. These rows start at the activity column and have the form:
+//
+//	<activity> <days> <time> <building/room> <date range> [instructor]
+//
+// when the columns are in the UC Merced order.
+type meetingRow struct {
+	values []string
+	cols   *Columns
+}
+
+// get returns the value in a column of the table.
+func (r *meetingRow) get(col int) (string, bool) {
+	i := col - r.cols.Activity
+	if i < 0 || i >= len(r.values) {
+		return "", false
 	}
-	exam.Time.Start, exam.Time.End, err = parseTime(values[2])
+	return r.values[i], true
+}
+
+func (r *meetingRow) fields() (days, times, room, dates string, err error) {
+	var ok [4]bool
+	days, ok[0] = r.get(r.cols.Days)
+	times, ok[1] = r.get(r.cols.Time)
+	room, ok[2] = r.get(r.cols.Room)
+	dates, ok[3] = r.get(r.cols.Dates)
+	if !ok[0] || !ok[1] || !ok[2] || !ok[3] {
+		err = errors.New("not enough values for a meeting time")
+	}
+	return
+}
+
+// parseMeeting will parse the row of an extra meeting time.
+// Anything missing from the row is taken from the course.
+func parseMeeting(values []string, year int, c *Course, cols *Columns) (*Meeting, error) {
+	r := meetingRow{values: values, cols: cols}
+	days, times, room, dates, err := r.fields()
+	if err != nil {
+		return nil, err
+	}
+	m := &Meeting{Activity: values[0], Instructor: c.Instructor}
+	m.Days = listDays(days)
+	m.Time.Start, m.Time.End, err = parseTime(times)
 	if err != nil {
 		return nil, err
 	}
-	date, err := parseDateRange(values[4], year)
+	m.BuildingRoom = room
+	date, err := parseDateRange(dates, year)
+	if err != nil {
+		return nil, err
+	}
+	m.Date = *date
+	if inst, ok := r.get(cols.Instructor); ok && strings.Trim(inst, " \u00a0") != "" {
+		m.Instructor = inst
+	}
+	return m, nil
+}
+
+func parseExam(values []string, year int, cols *Columns) (*Exam, error) {
+	r := meetingRow{values: values, cols: cols}
+	days, times, room, dates, err := r.fields()
+	if err != nil {
+		return nil, err
+	}
+	exam := &Exam{Building: room}
+	if d := listDays(days); len(d) > 0 {
+		exam.Day = d[0]
+	}
+	exam.Time.Start, exam.Time.End, err = parseTime(times)
+	if err != nil {
+		return nil, err
+	}
+	date, err := parseDateRange(dates, year)
 	if err != nil {
 		return nil, err
 	}
@@ -620,6 +807,10 @@ var client = http.Client{
 	Timeout: time.Second * 15,
 }
 
+// defaultClient is used by all the package level
+// functions and it uses the package level http client.
+var defaultClient = ClientFromClient(Merced, &client)
+
 // SetClientTimeout sets the client timeout
 func SetClientTimeout(tm time.Duration) {
 	client.Timeout = tm
@@ -630,49 +821,25 @@ func SetHTTPClient(c http.Client) {
 	client = c
 }
 
-const (
-	baseHost = "mystudentrecord.ucmerced.edu"
-	basePath = "/pls/PROD"
-)
+// SetTransport sets the transport used by the package level http client.
+func SetTransport(rt http.RoundTripper) {
+	client.Transport = rt
+}
 
-func getData(year, term, subject string, openclasses bool) (*http.Response, error) {
-	termcode, ok := terms[term]
-	if !ok {
-		return nil, fmt.Errorf("could not find term %s", term)
-	}
-	var open string
-	if openclasses {
-		open = "Y"
-	} else {
-		open = "N"
-	}
-	if subject == "" {
-		subject = "ALL"
-	}
-	params := &url.Values{
-		"validterm":   {fmt.Sprintf("%s%s", year, termcode)},
-		"openclasses": {open},
-		"subjcode":    {strings.ToUpper(subject)},
-	}
-	// TODO change this to POST the params as form data
-	// curl -s -X POST 'https://mystudentrecord.ucmerced.edu/pls/PROD/xhwschedule.P_ViewSchedule' --form validterm=202020 --form openclasses=N
-	req := &http.Request{
-		Method: "GET",
-		Proto:  "HTTP/1.1",
-		URL: &url.URL{
-			Scheme:   "https",
-			Host:     "mystudentrecord.ucmerced.edu",
-			Path:     filepath.Join(basePath, "/xhwschedule.P_ViewSchedule"),
-			RawQuery: params.Encode(),
-		},
-		Header: http.Header{
-			"User-Agent": {fmt.Sprintf("go-edu-%v", time.Now().Nanosecond())},
-		},
-	}
-	return client.Do(req)
+// SetBaseURL sets the url that all requests for the UC Merced
+// site are sent to. The url should include the path that the
+// schedule pages are under (i.e. https://host/pls/PROD).
+func SetBaseURL(u string) error {
+	if err := Merced.SetBaseURL(u); err != nil {
+		return err
+	}
+	return defaultClient.SetBaseURL(u)
 }
 
 var (
-	_ school.Schedule = (*Schedule)(nil)
-	_ school.Course   = (*Course)(nil)
+	_ school.Schedule  = (*Schedule)(nil)
+	_ school.Course    = (*Course)(nil)
+	_ school.Sequencer = (*Course)(nil)
+	_ school.Examiner  = (*Course)(nil)
+	_ school.Grouper   = (*Schedule)(nil)
 )
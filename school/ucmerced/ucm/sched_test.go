@@ -2,11 +2,23 @@ package ucm
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/harrybrwn/edu/school"
 )
 
 const (
@@ -15,16 +27,65 @@ const (
 )
 
 var (
-	mu   sync.Mutex
 	once sync.Once
 	raw  []byte
 )
 
+// basePath is the path that the test server
+// serves the schedule pages under.
+const basePath = "/pls/PROD"
+
+func TestMain(m *testing.M) {
+	srv := newTestServer()
+	if err := SetBaseURL(srv.URL + basePath); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
+
+// newTestServer creates a server that serves the recorded
+// pages in the testdata directory.
+func newTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(basePath+"/xhwschedule.P_ViewSchedule", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		term, subj := q.Get("validterm"), q.Get("subjcode")
+		serveFixture(w, r,
+			fmt.Sprintf("schedule-%s-%s.html", term, subj),
+			fmt.Sprintf("schedule-%s.html", term),
+		)
+	})
+	mux.HandleFunc(basePath+"/xhwschedule.P_ViewCrnDetail", func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(w, r, fmt.Sprintf("info-%s.html", r.URL.Query().Get("crn")))
+	})
+	mux.HandleFunc(basePath+"/xhwschedule.p_selectsubject", func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(w, r, "selectsubject.html")
+	})
+	return httptest.NewServer(mux)
+}
+
+// serveFixture will serve the first fixture that exists
+// or respond with a 404.
+func serveFixture(w http.ResponseWriter, r *http.Request, names ...string) {
+	for _, name := range names {
+		b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			continue
+		}
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Write(b)
+		return
+	}
+	http.NotFound(w, r)
+}
+
 func getTestData(t *testing.T) io.Reader {
 	t.Helper()
 	once.Do(func() {
 		var buf bytes.Buffer
-		resp, err := getData(fmt.Sprintf("%d", testyear), testterm, "", false)
+		resp, err := defaultClient.getData(context.Background(), fmt.Sprintf("%d", testyear), testterm, "", false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -41,11 +102,11 @@ func testSchedule(t *testing.T) Schedule {
 	t.Helper()
 	// note: uses data from spring 2021
 	r := getTestData(t)
-	rows, err := parseRows(r)
+	rows, err := parseRows(r, &DefaultColumns)
 	if err != nil {
 		t.Fatal(err)
 	}
-	sc, err := parse(rows, 2021)
+	sc, err := parse(rows, 2021, &DefaultColumns)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -57,14 +118,14 @@ func Test(t *testing.T) {
 
 func TestParser(t *testing.T) {
 	r := getTestData(t)
-	rows, err := parseRows(r)
+	rows, err := parseRows(r, &DefaultColumns)
 	if err != nil {
 		t.Fatal(err)
 	}
 	if len(rows) == 0 {
 		t.Fatal("did not parse any rows")
 	}
-	sc, err := parse(rows, 2021)
+	sc, err := parse(rows, 2021, &DefaultColumns)
 	if err != nil {
 		t.Error(err)
 	}
@@ -121,7 +182,7 @@ func TestInfo(t *testing.T) {
 	if len(sc) == 0 {
 		t.Fatal("testSchedule is empty")
 	}
-	for _, c := range sc {
+	for _, c := range sc.Ordered()[:2] {
 		info, err := c.Info()
 		if err != nil {
 			t.Error(err)
@@ -129,7 +190,87 @@ func TestInfo(t *testing.T) {
 		if len(info) == 0 {
 			t.Error("empty info string")
 		}
-		break
+	}
+}
+
+func TestFixtures(t *testing.T) {
+	sc := testSchedule(t)
+	if sc.Len() != 8 {
+		t.Errorf("wrong number of courses: got %d; want 8", sc.Len())
+	}
+	// the row with a bad crn and its exam should be skipped
+	for _, c := range sc {
+		if c.Fullcode == "CSE-175-01" {
+			t.Error("course with a bad crn should not be in the schedule")
+		}
+	}
+	exams := map[int]time.Weekday{
+		10001: time.Thursday,
+		30151: time.Monday,
+		30160: time.Wednesday,
+	}
+	for crn, day := range exams {
+		c := sc[crn]
+		if c.Exam == nil {
+			t.Errorf("%d should have an exam", crn)
+			continue
+		}
+		if c.Exam.Day != day {
+			t.Errorf("wrong exam day for %d: got %v; want %v", crn, c.Exam.Day, day)
+		}
+	}
+	if sc[30160].Exam.Date.Day() != 12 || sc[30160].Exam.Time.Start.Hour() != 11 {
+		t.Error("exam from the skipped course should not overwrite CSE-165's exam")
+	}
+	if sc[30152].Exam != nil {
+		t.Error("lab should not have an exam")
+	}
+	if e := sc[30160].FinalExam(); e == nil || e.StartDate.Day() != 12 || e.Start.Hour() != 11 {
+		t.Errorf("wrong final exam for 30160: %+v", e)
+	}
+	if sc[30152].FinalExam() != nil {
+		t.Error("lab should not have a final exam")
+	}
+
+	meetings := map[int]int{30151: 2, 30152: 2, 30153: 1, 30201: 2, 34936: 1}
+	for crn, n := range meetings {
+		if len(sc[crn].Meetings) != n {
+			t.Errorf("wrong number of meetings for %d: got %d; want %d", crn, len(sc[crn].Meetings), n)
+		}
+	}
+	if sc[30152].Meetings[1].Instructor != "Lee, Amy" {
+		t.Error("wrong instructor for the second lab meeting")
+	}
+
+	tbd := sc[30200]
+	if !tbd.Time.Start.IsZero() || !tbd.Time.End.IsZero() {
+		t.Error("TBD times should be zero")
+	}
+	if len(tbd.Days) != 0 {
+		t.Errorf("TBD course should not have any days: %v", tbd.Days)
+	}
+	if tbd.SeatsOpen() != 0 {
+		t.Error("closed course should have no seats")
+	}
+	if tbd.Title != "Linear Alg & Diff EquationsClass is fully online" {
+		t.Errorf("wrong title %q", tbd.Title)
+	}
+
+	if secs := sc.SectionsOf(30151); len(secs) != 2 {
+		t.Errorf("CSE-100-01 should have 2 labs, got %d", len(secs))
+	}
+}
+
+func TestGetOfferings(t *testing.T) {
+	o, err := GetOfferings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = o.Check(testyear, testterm, "cse"); err != nil {
+		t.Error(err)
+	}
+	if len(o.SubjectCodes()) != 4 {
+		t.Errorf("wrong number of subjects: %v", o.SubjectCodes())
 	}
 }
 
@@ -184,6 +325,464 @@ func TestGetFall(t *testing.T) {
 	}
 }
 
+func TestParseMultiMeeting(t *testing.T) {
+	rows := []*row{
+		{kind: kindHeader},
+		{kind: kindCourse, crn: 30151, values: []string{
+			"30151", "CSE-100-01", "Algorithm Design and Analysis", "4", "LECT", "MW",
+			"10:30-11:45am", "COB2 130", "25-JAN 07-MAY", "Smith, John", "120", "100", "20",
+		}},
+		{kind: kindMultiLect, values: []string{"LECT", "F", "1:30-2:20pm", "CLSSRM 102", "25-JAN 07-MAY"}},
+		{kind: kindExam, values: []string{"EXAM", "W", "8:00-11:00am", "COB2 130", "12-MAY 12-MAY"}},
+		{kind: kindCourse, crn: 30152, values: []string{
+			"30152", "CSE-100-02L", "Algorithm Design and Analysis", "0", "LAB", "T",
+			"7:30-10:20pm", "SE1 100", "25-JAN 07-MAY", "Doe, Jane", "30", "30", "Closed",
+		}},
+		{kind: kindMultiLab, values: []string{"LAB", "R", "TBD-TBD", "SE1 138", "25-JAN 07-MAY", "Doe, John"}},
+	}
+	sc, err := parse(rows, testyear, &DefaultColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lect := sc[30151]
+	if len(lect.Meetings) != 2 {
+		t.Fatalf("expected 2 lecture meetings, got %d", len(lect.Meetings))
+	}
+	if lect.Exam == nil {
+		t.Error("exam should be attached after a multi-meeting row")
+	}
+	m := lect.Meetings[1]
+	if len(m.Days) != 1 || m.Days[0] != time.Friday {
+		t.Errorf("wrong days for second meeting: %v", m.Days)
+	}
+	if m.Time.Start.Hour() != 13 || m.BuildingRoom != "CLSSRM 102" {
+		t.Errorf("wrong second meeting: %+v", m)
+	}
+	if m.Instructor != lect.Instructor {
+		t.Error("meeting without an instructor should use the course instructor")
+	}
+	if lect.Meetings[0].BuildingRoom != lect.BuildingRoom {
+		t.Error("first meeting should be the same as the course")
+	}
+	lab := sc[30152]
+	if len(lab.Meetings) != 2 {
+		t.Fatalf("expected 2 lab meetings, got %d", len(lab.Meetings))
+	}
+	if lab.Meetings[1].Instructor != "Doe, John" {
+		t.Errorf("wrong instructor %q", lab.Meetings[1].Instructor)
+	}
+	if !lab.Meetings[1].Time.Start.IsZero() {
+		t.Error("TBD meeting should have a zero start time")
+	}
+
+	if _, err = parse([]*row{{kind: kindHeader}, rows[2]}, testyear, &DefaultColumns); err == nil {
+		t.Error("expected an error for a meeting row without a course")
+	}
+}
+
+func TestSectionLinks(t *testing.T) {
+	course := func(crn int, code, activity, title string) []string {
+		return []string{
+			fmt.Sprint(crn), code, title, "4", activity, "MW", "10:30-11:45am",
+			"COB2 130", "25-JAN 07-MAY", "Smith, John", "30", "10", "20",
+		}
+	}
+	rows := []*row{{kind: kindHeader}}
+	for _, c := range [][]string{
+		course(30100, "CSE-100-01", "LECT", "Algorithms Must Also Register for a Corresponding Lab"),
+		course(30101, "CSE-100-02L", "LAB", "Algorithms Must Also Register for a Corresponding Lecture"),
+		course(30102, "CSE-100-03L", "LAB", "Algorithms Must Also Register for a Corresponding Lecture"),
+		course(30110, "CSE-100-04", "LECT", "Algorithms Must Also Register for a Corresponding Lab"),
+		course(30111, "CSE-100-05L", "LAB", "Algorithms Must Also Register for a Corresponding Lecture"),
+		course(30200, "MATH-024-01", "LECT", "Linear Algebra Must Also Register for one of 30202"),
+		course(30201, "MATH-024-02D", "DISC", "Linear Algebra"),
+		course(30202, "MATH-024-03D", "DISC", "Linear Algebra"),
+		course(30300, "WRI-010-01", "LECT", "College Reading and Composition"),
+		course(30400, "PHYS-008-01", "LECT", "Physics Must Also Register for one of 30401 30402"),
+		course(30401, "PHYS-008-02L", "LAB", "Physics"),
+		course(30402, "PHYS-008-03L", "LAB", "Physics"),
+		course(30403, "PHYS-008-04", "LECT", "Physics Must Also Register for one of 30402 30404"),
+		course(30404, "PHYS-008-05L", "LAB", "Physics"),
+	} {
+		crn, _ := strconv.Atoi(c[0])
+		rows = append(rows, &row{kind: kindCourse, crn: crn, values: c})
+	}
+	sc, err := parse(rows, testyear, &DefaultColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc[30100].Title != "Algorithms" {
+		t.Errorf("note should be split from title, got %q", sc[30100].Title)
+	}
+	tests := map[int][]int{
+		30100: {30101, 30102},
+		30110: {30111},
+		30200: {30202},
+		30300: {},
+		30400: {30401, 30402},
+		30403: {30404},
+	}
+	for lect, exp := range tests {
+		secs := sc.SectionsOf(lect)
+		if len(secs) != len(exp) {
+			t.Errorf("wrong number of sections for %d: got %d; want %d", lect, len(secs), len(exp))
+			continue
+		}
+		for i, sec := range secs {
+			if sec.CRN != exp[i] {
+				t.Errorf("wrong section: got %d; want %d", sec.CRN, exp[i])
+			}
+			if l := sc.LectureOf(sec.CRN); l == nil || l.CRN != lect {
+				t.Errorf("section %d should link back to %d", sec.CRN, lect)
+			}
+		}
+	}
+	if sc.LectureOf(30201) != nil {
+		t.Error("30201 should not be linked to a lecture")
+	}
+	groups := sc.Groups()
+	if len(groups) != 7 {
+		t.Fatalf("wrong number of groups: got %d; want 7", len(groups))
+	}
+	if groups[0].Course.CRN != 30100 || len(groups[0].Sections) != 2 {
+		t.Error("first group should be CSE-100-01 and its labs")
+	}
+}
+
+func TestParseOfferings(t *testing.T) {
+	page := `<html><body><div class="pagebodydiv">
+<form action="xhwschedule.P_ViewSchedule" method="post">
+<select name="validterm">
+  <option value="202130">Fall Semester 2021</option>
+  <option value="202120">Summer Session 2021</option>
+  <option value="202110" selected>Spring Semester 2021</option>
+</select>
+<select name="subjcode">
+  <option value="ALL">All Subjects</option>
+  <option value="ANTH">Anthropology</option>
+  <option value="CSE">Computer Science and Engineering</option>
+  <option value="MATH">Mathematics</option>
+</select>
+</form></div></body></html>`
+	o, err := parseOfferings(strings.NewReader(page), terms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(o.Subjects) != 3 {
+		t.Errorf("expected 3 subjects, got %d", len(o.Subjects))
+	}
+	if o.Subjects["CSE"] != "Computer Science and Engineering" {
+		t.Errorf("wrong subject name %q", o.Subjects["CSE"])
+	}
+	if len(o.Terms) != 3 {
+		t.Fatalf("expected 3 terms, got %d", len(o.Terms))
+	}
+	if tm := o.Term(2021, "Fall"); tm == nil || tm.Code != "202130" {
+		t.Error("could not find fall 2021")
+	}
+	for _, tc := range []struct {
+		year          int
+		term, subject string
+		ok            bool
+	}{
+		{2021, "spring", "cse", true},
+		{2021, "summer", "", true},
+		{2021, "sprng", "cse", false},
+		{2020, "spring", "cse", false},
+		{2021, "spring", "CES", false},
+	} {
+		err = o.Check(tc.year, tc.term, tc.subject)
+		if tc.ok && err != nil {
+			t.Error(err)
+		} else if !tc.ok && err == nil {
+			t.Errorf("expected an error for %d %s %s", tc.year, tc.term, tc.subject)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	sc := testSchedule(t)
+	opts, err := sc.Plan([]string{"CSE 100", "anth-1"}, Preferences{Earliest: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) != 2 {
+		t.Fatalf("wrong number of options: got %d; want 2", len(opts))
+	}
+	if crns := opts[0].CRNs(); len(crns) != 3 || crns[1] != 30153 {
+		t.Errorf("the option without early labs should be first, got %v", crns)
+	}
+	if opts[0].Early != 0 || opts[1].Early != 2 {
+		t.Errorf("wrong early counts: %d, %d", opts[0].Early, opts[1].Early)
+	}
+	if opts[1].Days != 5 {
+		t.Errorf("wrong number of days: got %d; want 5", opts[1].Days)
+	}
+	best, err := sc.Plan([]string{"CSE 100", "anth-1"}, Preferences{Earliest: 9, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(best) != 1 || !reflect.DeepEqual(best[0].CRNs(), opts[0].CRNs()) {
+		t.Errorf("a limit should keep the best option, got %v", best)
+	}
+
+	opts, err = sc.Plan([]string{"CSE 100", "ANTH 1"}, Preferences{
+		Instructors: []string{"staff"},
+		Rank:        []Criterion{ByGaps},
+		Limit:       1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) != 1 {
+		t.Fatal("limit should be applied")
+	}
+	if opts[0].Preferred != 1 {
+		t.Errorf("wrong number of preferred instructors: %d", opts[0].Preferred)
+	}
+
+	// WRI 10 conflicts with every CSE 100 option
+	opts, err = sc.Plan([]string{"CSE 100", "WRI 10"}, Preferences{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) != 0 {
+		t.Errorf("expected no options, got %d", len(opts))
+	}
+	if _, err = sc.Plan([]string{"CSE 999"}, Preferences{}); err == nil {
+		t.Error("expected an error for a course that is not offered")
+	}
+	if _, err = sc.Plan([]string{"100"}, Preferences{}); err == nil {
+		t.Error("expected an error for a course without a subject")
+	}
+	if _, err = sc.Plan([]string{"CSE 100"}, Preferences{Rank: []Criterion{"lunch"}}); err == nil {
+		t.Error("expected an error for an unknown ranking criterion")
+	}
+	for _, name := range []string{"days", " Gaps", "EARLY"} {
+		if _, err = ParseCriterion(name); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err = ParseCriterion("lunch"); err == nil {
+		t.Error("expected an error for an unknown ranking criterion")
+	}
+}
+
+func TestPlanUnlinkedLabs(t *testing.T) {
+	sc := Schedule{
+		40001: {CRN: 40001, Fullcode: "BIO-001-01", Subject: "BIO", Number: 1, Section: "01", Activity: "LECT"},
+		40002: {CRN: 40002, Fullcode: "BIO-001-02L", Subject: "BIO", Number: 1, Section: "02L", Activity: "LAB", order: 1},
+		40003: {CRN: 40003, Fullcode: "BIO-001-03L", Subject: "BIO", Number: 1, Section: "03L", Activity: "LAB", order: 2},
+	}
+	if len(sc.Groups()) != 3 {
+		t.Fatal("the labs should not be linked to the lecture")
+	}
+	opts, err := sc.Plan([]string{"BIO 1"}, Preferences{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) == 0 {
+		t.Fatal("expected an option with the lecture")
+	}
+	for _, opt := range opts {
+		if crns := opt.CRNs(); crns[0] != 40001 {
+			t.Errorf("every option should have the lecture, got %v", crns)
+		}
+	}
+}
+
+func TestCourseIdent(t *testing.T) {
+	sc := testSchedule(t)
+	for _, tc := range []struct {
+		id   string
+		want []int
+	}{
+		{"CSE 100", []int{30151, 30152, 30153}},
+		{"CSE-100-02", []int{30152}},
+		{"CSE 100-02", []int{30152}},
+		{"CSE-100-02L", []int{30152}},
+		{"cse-100-3", []int{30153}},
+		{"CSE 100 LAB", []int{30152, 30153}},
+		{"MATH-024-02D", []int{30201}},
+		{"MATH 24 02", []int{30201}},
+		{"CSE-100-02D", nil},
+	} {
+		id, err := school.ParseCourseIdent(tc.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var crns []int
+		for _, c := range sc.Ordered() {
+			if id.Match(c) {
+				crns = append(crns, c.CRN)
+			}
+		}
+		sort.Ints(crns)
+		if !reflect.DeepEqual(crns, tc.want) {
+			t.Errorf("%q: got %v; want %v", tc.id, crns, tc.want)
+		}
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	sc := testSchedule(t)
+	var buf bytes.Buffer
+	err := WriteICS(&buf, []*Course{sc[30151], sc[30200]}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ics := buf.String()
+	for _, exp := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:30151-0@edu\r\n",
+		"DTSTART:20210126T133000\r\n",
+		"DTEND:20210126T144500\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20210507T235900\r\n",
+		"UID:30151-1@edu\r\n",
+		"DTSTART:20210129T093000\r\n",
+		"LOCATION:COB2 140\r\n",
+		"SUMMARY:CSE-100-01 Final Exam\r\n",
+		"DTSTART:20210510T150000\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, exp) {
+			t.Errorf("calendar should contain %q", exp)
+		}
+	}
+	if strings.Contains(ics, "UID:30200") {
+		t.Error("TBD course should not have any events")
+	}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line is longer than 75 octets: %q", line)
+		}
+	}
+
+	buf.Reset()
+	pst := time.FixedZone("PST", -8*60*60)
+	if err = WriteICS(&buf, []*Course{sc[30151]}, pst); err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{
+		"DTSTART;TZID=PST:20210126T133000\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20210508T075900Z\r\n",
+		"BEGIN:VTIMEZONE\r\nTZID:PST\r\nBEGIN:STANDARD\r\n",
+		"TZOFFSETTO:-0800\r\n",
+	} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("calendar should contain %q", exp)
+		}
+	}
+}
+
+func TestWriteICSDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	c := &Course{CRN: 10001, Fullcode: "CSE-031-01", Activity: "LECT"}
+	c.Days = []time.Weekday{time.Monday, time.Wednesday}
+	c.Time.Start = time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+	c.Time.End = time.Date(0, 1, 1, 10, 15, 0, 0, time.UTC)
+	c.Date.Start = time.Date(2021, time.August, 25, 0, 0, 0, 0, time.UTC)
+	c.Date.End = time.Date(2021, time.December, 10, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err = WriteICS(&buf, []*Course{c}, loc); err != nil {
+		t.Fatal(err)
+	}
+	ics := buf.String()
+	for _, exp := range []string{
+		// the class stays at 9:00 after daylight saving ends on Nov 7
+		"DTSTART;TZID=America/Los_Angeles:20210825T090000\r\n",
+		"UNTIL=20211211T075900Z\r\n",
+		"BEGIN:VTIMEZONE\r\nTZID:America/Los_Angeles\r\n",
+		"BEGIN:DAYLIGHT\r\nDTSTART:20210825T000000\r\nTZOFFSETFROM:-0700\r\nTZOFFSETTO:-0700\r\nTZNAME:PDT\r\nEND:DAYLIGHT\r\n",
+		"BEGIN:STANDARD\r\nDTSTART:20211107T020000\r\nTZOFFSETFROM:-0700\r\nTZOFFSETTO:-0800\r\nTZNAME:PST\r\nEND:STANDARD\r\n",
+	} {
+		if !strings.Contains(ics, exp) {
+			t.Errorf("calendar should contain %q\n%s", exp, ics)
+		}
+	}
+	if strings.Contains(ics, "20210825T160000Z") {
+		t.Error("class times should not be in utc")
+	}
+}
+
+func TestExamConflicts(t *testing.T) {
+	sc := testSchedule(t)
+	c := sc[30160]
+	if len(c.Exams) != 2 {
+		t.Fatalf("CSE-165 should have 2 exams, got %d", len(c.Exams))
+	}
+	if c.Exam != c.Exams[0] || c.Exam.Day != time.Wednesday {
+		t.Error("Exam should be the first exam")
+	}
+	conflicts := ExamConflicts([]*Course{sc[10001], sc[30151], sc[30160], sc[34936], sc[30152]})
+	if len(conflicts) != 2 {
+		t.Fatalf("wrong number of conflicts: got %d; want 2", len(conflicts))
+	}
+	// sorted by date
+	sameDay, overlap := conflicts[0], conflicts[1]
+	if sameDay.Overlap || sameDay.A.CRN != 30151 || sameDay.B.CRN != 30160 {
+		t.Errorf("expected CSE-100 and CSE-165 exams on the same day: %d %d", sameDay.A.CRN, sameDay.B.CRN)
+	}
+	if !overlap.Overlap || overlap.A.CRN != 10001 || overlap.B.CRN != 34936 {
+		t.Errorf("expected ANTH-001 and WRI-010 exams to overlap: %d %d", overlap.A.CRN, overlap.B.CRN)
+	}
+}
+
+func TestDescriptions(t *testing.T) {
+	sc := testSchedule(t)
+	var (
+		descs    = make(Descriptions)
+		progress int
+	)
+	err := descs.Fetch(context.Background(), []*Course{sc[10001], sc[30151], sc[30152]}, FetchOptions{
+		Workers:  2,
+		Interval: time.Millisecond,
+		Progress: func(done, total int) { progress = done },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(descs) != 2 || progress != 2 {
+		t.Errorf("each course should only be fetched once: got %d", len(descs))
+	}
+	cse := descs["CSE-100"]
+	if cse == nil {
+		t.Fatal("no description for CSE-100")
+	}
+	if cse.Prerequisites != "CSE 030 and MATH 021" {
+		t.Errorf("wrong prerequisites %q", cse.Prerequisites)
+	}
+	if err = descs.Fetch(context.Background(), []*Course{sc[30160]}, FetchOptions{}); err == nil {
+		t.Error("expected an error for a missing info page")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	partial := Descriptions{"CSE-100": cse}
+	if err = partial.Fetch(ctx, []*Course{sc[10001], sc[30160]}, FetchOptions{}); err != context.Canceled {
+		t.Errorf("expected the fetch to be canceled, got %v", err)
+	}
+	if len(partial) != 1 || partial["CSE-100"] != cse {
+		t.Error("a canceled fetch should keep the descriptions it has")
+	}
+
+	res := descs.Search(sc.Ordered(), "dynamic ALGORITHMS")
+	if len(res) != 1 || res[0].CourseKey() != "CSE-100" {
+		t.Errorf("wrong search results: %v", res)
+	}
+	if res = descs.Search(sc.Ordered(), "object"); len(res) != 1 || res[0].CRN != 30160 {
+		t.Error("search should include titles")
+	}
+
+	d := ParseDescription("Intro to things. Prerequisites: MATH 021. Corequisite: PHYS 008")
+	if d.Prerequisites != "MATH 021" || d.Corequisites != "PHYS 008" {
+		t.Errorf("wrong requisites: %+v", d)
+	}
+}
+
 func TestParseTime(t *testing.T) {
 	testcases := []struct {
 		str        string
@@ -247,3 +846,32 @@ func cmp(a, b *Course) bool {
 		a.Enrolled == b.Enrolled &&
 		a.seats == b.seats
 }
+
+func TestClient(t *testing.T) {
+	var agent string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent = r.UserAgent()
+		serveFixture(w, r, "selectsubject.html")
+	}))
+	defer mirror.Close()
+	c := NewClient(Merced)
+	if err := c.SetBaseURL(mirror.URL + "/mirror"); err != nil {
+		t.Fatal(err)
+	}
+	c.UserAgent = "edu-test"
+	if _, err := c.Offerings(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if agent != "edu-test" {
+		t.Errorf("wrong user agent: %q", agent)
+	}
+	if Merced.BaseURL.Host == c.Site().BaseURL.Host {
+		t.Error("setting the client's base url should not change the site")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := defaultClient.Schedule(ctx, testyear, testterm, "", false); err == nil {
+		t.Error("expected an error from a canceled context")
+	}
+}
@@ -0,0 +1,159 @@
+package ucm
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/harrybrwn/edu/school"
+)
+
+var (
+	mustAlsoRegex = regexp.MustCompile(`Must Also.*$`)
+	crnRegex      = regexp.MustCompile(`\b[0-9]{5}\b`)
+)
+
+// splitNote will split the "Must Also Register for..." note
+// from the end of a course title.
+func splitNote(title string) (string, string) {
+	loc := mustAlsoRegex.FindStringIndex(title)
+	if loc == nil {
+		return title, ""
+	}
+	return strings.TrimSpace(title[:loc[0]]), title[loc[0]:]
+}
+
+// Group is a course and all of the sections that must be
+// registered along with it.
+type Group struct {
+	Course   *Course
+	Sections []*Course
+}
+
+// LectureOf returns the lecture that a section must be
+// registered with. Returns nil if there is no linked lecture.
+func (s *Schedule) LectureOf(crn int) *Course {
+	c, ok := (*s)[crn]
+	if !ok || c.Lecture == 0 {
+		return nil
+	}
+	return (*s)[c.Lecture]
+}
+
+// SectionsOf returns the labs, discussions, etc. that must
+// be registered with a lecture.
+func (s *Schedule) SectionsOf(crn int) []*Course {
+	c, ok := (*s)[crn]
+	if !ok {
+		return nil
+	}
+	sections := make([]*Course, 0, len(c.Linked))
+	for _, id := range c.Linked {
+		if sec, ok := (*s)[id]; ok {
+			sections = append(sections, sec)
+		}
+	}
+	return sections
+}
+
+// Groups returns the schedule in its original order with each
+// section grouped under the lecture that it is linked to.
+func (s *Schedule) Groups() []Group {
+	groups := make([]Group, 0, len(*s))
+	for _, c := range s.Ordered() {
+		if _, ok := (*s)[c.Lecture]; ok && c.Lecture != 0 {
+			continue // printed with its lecture
+		}
+		groups = append(groups, Group{
+			Course:   c,
+			Sections: s.SectionsOf(c.CRN),
+		})
+	}
+	return groups
+}
+
+// SectionGroups returns the same groups as Groups
+// but as generic courses.
+func (s *Schedule) SectionGroups() []school.Group {
+	groups := s.Groups()
+	list := make([]school.Group, len(groups))
+	for i, g := range groups {
+		list[i].Course = g.Course
+		list[i].Sections = make([]school.Course, len(g.Sections))
+		for j, sec := range g.Sections {
+			list[i].Sections[j] = sec
+		}
+	}
+	return list
+}
+
+// link will build the section graph between lectures and
+// the sections that must be registered with them.
+//
+// If a note lists CRNs explicitly then those are used,
+// otherwise sections are linked to the closest lecture of
+// the same course that comes before them in the schedule.
+func link(sch Schedule) {
+	var lectures = make(map[string]*Course)
+	for _, c := range sch.Ordered() {
+		key := c.Subject + strconv.Itoa(c.Number)
+		if c.Activity == string(Lecture) {
+			crns := noteCRNs(c.Note)
+			if len(crns) == 0 {
+				lectures[key] = c
+				continue
+			}
+			// the note is explicit so don't guess
+			delete(lectures, key)
+			for _, crn := range crns {
+				if sec, ok := sch[crn]; ok && sec != c {
+					linkSections(c, sec)
+				}
+			}
+			continue
+		}
+		if c.Lecture != 0 {
+			continue
+		}
+		for _, crn := range noteCRNs(c.Note) {
+			if lect, ok := sch[crn]; ok && lect.Activity == string(Lecture) {
+				linkSections(lect, c)
+			}
+		}
+		if c.Lecture != 0 {
+			continue
+		}
+		lect, ok := lectures[key]
+		if !ok || (lect.Note == "" && c.Note == "") {
+			continue
+		}
+		linkSections(lect, c)
+	}
+}
+
+// linkSections will link a section to a lecture. A section
+// can only be registered with one lecture so the first
+// lecture it is linked to is kept.
+func linkSections(lecture, section *Course) {
+	if section.Lecture != 0 {
+		return
+	}
+	section.Lecture = lecture.CRN
+	lecture.Linked = append(lecture.Linked, section.CRN)
+}
+
+func noteCRNs(note string) []int {
+	if note == "" {
+		return nil
+	}
+	matches := crnRegex.FindAllString(note, -1)
+	crns := make([]int, 0, len(matches))
+	for _, m := range matches {
+		crn, err := strconv.Atoi(m)
+		if err != nil {
+			continue
+		}
+		crns = append(crns, crn)
+	}
+	return crns
+}
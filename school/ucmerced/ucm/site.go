@@ -0,0 +1,132 @@
+package ucm
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/harrybrwn/errs"
+)
+
+// Columns holds the index of each column in the
+// table of a Banner 8 schedule page.
+type Columns struct {
+	CRN        int
+	Code       int
+	Title      int
+	Units      int
+	Activity   int
+	Days       int
+	Time       int
+	Room       int
+	Dates      int
+	Instructor int
+	Capacity   int
+	Enrolled   int
+	Seats      int
+	// Count is the number of columns in the table.
+	Count int
+}
+
+// DefaultColumns is the column layout used by UC Merced.
+var DefaultColumns = Columns{
+	CRN:        0,
+	Code:       1,
+	Title:      2,
+	Units:      3,
+	Activity:   4,
+	Days:       5,
+	Time:       6,
+	Room:       7,
+	Dates:      8,
+	Instructor: 9,
+	Capacity:   10,
+	Enrolled:   11,
+	Seats:      12,
+	Count:      13,
+}
+
+// Validate makes sure that every column is in the table.
+func (c *Columns) Validate() error {
+	for _, i := range []int{
+		c.CRN, c.Code, c.Title, c.Units, c.Activity, c.Days, c.Time,
+		c.Room, c.Dates, c.Instructor, c.Capacity, c.Enrolled, c.Seats,
+	} {
+		if i < 0 || i >= c.Count {
+			return fmt.Errorf("column %d is not in a table of %d columns", i, c.Count)
+		}
+	}
+	return nil
+}
+
+// Site is a school that runs the Ellucian Banner 8 schedule
+// pages (xhwschedule.P_ViewSchedule).
+type Site struct {
+	// BaseURL is the url that the schedule pages are
+	// under, i.e. https://host/pls/PROD
+	BaseURL url.URL
+	// Terms maps term names to the suffix of the term code,
+	// the full term code is the year followed by the suffix.
+	Terms   map[string]string
+	Columns Columns
+	// TimeZone is the name of the time zone that
+	// the schedule is in, it may be empty.
+	TimeZone string
+}
+
+// Merced is the site for UC Merced, it is used
+// by all the package level functions.
+var Merced = &Site{
+	BaseURL: url.URL{
+		Scheme: "https",
+		Host:   "mystudentrecord.ucmerced.edu",
+		Path:   "/pls/PROD",
+	},
+	Terms:    terms,
+	Columns:  DefaultColumns,
+	TimeZone: "America/Los_Angeles",
+}
+
+// NewSite creates a new site.
+func NewSite(base string, terms map[string]string, cols Columns) (*Site, error) {
+	if len(terms) == 0 {
+		return nil, errs.New("a site needs at least one term")
+	}
+	if err := cols.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Site{Terms: terms, Columns: cols}
+	if err := s.SetBaseURL(base); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetBaseURL sets the url that all requests for the site are sent to.
+func (s *Site) SetBaseURL(u string) error {
+	base, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return fmt.Errorf("base url %q must have a scheme and host", u)
+	}
+	s.BaseURL = *base
+	return nil
+}
+
+// Get gets the schedule
+func (s *Site) Get(year int, term string, open bool) (Schedule, error) {
+	return s.BySubject(year, term, "", open)
+}
+
+// BySubject gets the schedule and only one subject given a subject code.
+func (s *Site) BySubject(year int, term, subject string, open bool) (Schedule, error) {
+	return ClientFromClient(s, &client).Schedule(context.Background(), year, term, subject, open)
+}
+
+// Offerings will get the subjects and terms offered
+// from the subject selection page.
+func (s *Site) Offerings() (*Offerings, error) {
+	return ClientFromClient(s, &client).Offerings(context.Background())
+}
@@ -0,0 +1,153 @@
+package ucm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Term is a term that is listed on the schedule's
+// subject selection page.
+type Term struct {
+	// Code is the term code used by the schedule (i.e. 202110)
+	Code string
+	// Name is the display name (i.e. "Spring Semester 2021")
+	Name   string
+	Year   int
+	Season string
+}
+
+// Offerings is the list of subjects and terms that
+// can be used to get a schedule.
+type Offerings struct {
+	// Subjects maps subject codes to subject names
+	Subjects map[string]string
+	Terms    []Term
+}
+
+// GetOfferings will get the subjects and terms offered
+// from the subject selection page.
+func GetOfferings() (*Offerings, error) {
+	return Merced.Offerings()
+}
+
+// SubjectsOffered will pull down the courses offered for a semester
+func SubjectsOffered(config ScheduleConfig) (map[string]string, error) {
+	o, err := GetOfferings()
+	if err != nil {
+		return nil, err
+	}
+	return o.Subjects, nil
+}
+
+// TermsOffered will get the list of terms that have
+// a schedule available.
+func TermsOffered() ([]Term, error) {
+	o, err := GetOfferings()
+	if err != nil {
+		return nil, err
+	}
+	return o.Terms, nil
+}
+
+// Term will find the term given the year and season.
+// Returns nil if the term is not offered.
+func (o *Offerings) Term(year int, season string) *Term {
+	season = strings.ToLower(season)
+	for i, t := range o.Terms {
+		if t.Year == year && t.Season == season {
+			return &o.Terms[i]
+		}
+	}
+	return nil
+}
+
+// SubjectCodes returns a sorted list of subject codes.
+func (o *Offerings) SubjectCodes() []string {
+	codes := make([]string, 0, len(o.Subjects))
+	for code := range o.Subjects {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Check will return an error if the term, year, or
+// subject are not offered. An empty subject is always valid.
+func (o *Offerings) Check(year int, season, subject string) error {
+	if o.Term(year, season) == nil {
+		return fmt.Errorf("term %q is not offered for %d", season, year)
+	}
+	subject = strings.ToUpper(subject)
+	if subject == "" || subject == "ALL" {
+		return nil
+	}
+	if _, ok := o.Subjects[subject]; !ok {
+		return fmt.Errorf("unknown subject %q", subject)
+	}
+	return nil
+}
+
+// parseOfferings parses the subject selection page. The
+// season of each term is found with the site's term codes.
+func parseOfferings(r io.Reader, terms map[string]string) (*Offerings, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	o := &Offerings{Subjects: make(map[string]string)}
+	doc.Find("select[name=subjcode] option").Each(func(i int, s *goquery.Selection) {
+		code, ok := s.Attr("value")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !ok || code == "" || code == "ALL" {
+			return
+		}
+		o.Subjects[code] = strings.TrimSpace(s.Text())
+	})
+	doc.Find("select[name=validterm] option").Each(func(i int, s *goquery.Selection) {
+		code, ok := s.Attr("value")
+		if !ok {
+			return
+		}
+		if t, err := newTerm(strings.TrimSpace(code), strings.TrimSpace(s.Text()), terms); err == nil {
+			o.Terms = append(o.Terms, *t)
+		}
+	})
+	if len(o.Subjects) == 0 && len(o.Terms) == 0 {
+		return nil, errors.New("no subjects or terms found")
+	}
+	return o, nil
+}
+
+func newTerm(code, name string, terms map[string]string) (*Term, error) {
+	if len(code) != 6 {
+		return nil, fmt.Errorf("bad term code %q", code)
+	}
+	year, err := strconv.Atoi(code[:4])
+	if err != nil {
+		return nil, err
+	}
+	t := &Term{Code: code, Name: name, Year: year}
+	for season, c := range terms {
+		if c == code[4:] {
+			t.Season = season
+			break
+		}
+	}
+	if t.Season == "" {
+		// fall back to the name if the code is unknown
+		lower := strings.ToLower(name)
+		for season := range terms {
+			if strings.Contains(lower, season) {
+				t.Season = season
+				break
+			}
+		}
+	}
+	return t, nil
+}